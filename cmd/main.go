@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/schraf/assistant/pkg/eval"
 	"github.com/schraf/assistant/pkg/generators"
 	"github.com/schraf/assistant/pkg/models"
+	"github.com/schraf/newspaper-assistant/internal/newspaper"
 	_ "github.com/schraf/newspaper-assistant/pkg/generator"
 )
 
@@ -17,6 +21,27 @@ func main() {
 	maxLength := flag.Int("length", 60000, "Max legnth of newspaper document")
 	title := flag.String("title", "", "Name of the newspaper section")
 	description := flag.String("description", "", "Description of the newspaper section")
+	sectionsFile := flag.String("sections", "", "JSON file with a list of newspaper sections, used instead of title and description")
+	depth := flag.Int("depth", 0, "Research depth: number of follow-up research rounds per article (0-2)")
+	sources := flag.Bool("sources", false, "Include a list of research sources after each article")
+	articles := flag.Int("articles", 0, "Number of planned stories to research per section, holding the rest in reserve (0 uses all)")
+	retryBlocked := flag.Bool("retry-blocked", false, "Retry content blocked research with a strictly factual prompt")
+	substituteBlocked := flag.Bool("substitute-blocked", false, "Replace stories whose research is content blocked with a reserve story")
+	factCheck := flag.String("fact-check", "off", "Fact check synthesized articles against research: off, revise or drop")
+	dateStrictness := flag.String("date-strictness", "off", "Handling of sentences dated before the date range: off, warn, revise or drop")
+	checkNumbers := flag.Bool("check-numbers", false, "Verify every figure in an article appears in its research notes")
+	copyThreshold := flag.Float64("copy-threshold", 0, "Fraction of an article that may be copied word for word from its research before a rewrite is requested (0 disables)")
+	reviewRounds := flag.Int("review-rounds", 0, "Number of editor review and revision rounds per article (0-2)")
+	condense := flag.Bool("condense", false, "Condense articles trimmed to fit the max length instead of only dropping their last paragraphs")
+	minFill := flag.Float64("min-fill", 0, "Fraction of the max length to fill by writing reserve or newly planned stories when the edition falls short (0 disables)")
+	judge := flag.Bool("judge-research", false, "Ask the assistant to judge whether each article's research is sufficient")
+	allow := flag.String("allow", "", "Comma separated list of the only domains research may use")
+	block := flag.String("block", "", "Comma separated list of domains research must not use")
+	prefer := flag.String("prefer", "", "Comma separated list of outlets research should prefer")
+	cacheDir := flag.String("cache-dir", defaultCacheDir(), "Directory to cache article research in (empty disables the cache)")
+	cacheTTL := flag.Duration("cache-ttl", 24*time.Hour, "How long cached research stays fresh (0 never expires)")
+	noCache := flag.Bool("no-cache", false, "Bypass the research cache for this run")
+	clearCache := flag.Bool("clear-cache", false, "Clear the research cache before running")
 	flag.Parse()
 
 	if *daysBack <= 0 {
@@ -31,30 +56,92 @@ func main() {
 		os.Exit(1)
 	}
 
-	if *title == "" {
+	if *depth < 0 || *depth > 2 {
+		fmt.Fprintf(os.Stderr, "Error: argument depth must be between 0 and 2\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *reviewRounds < 0 || *reviewRounds > 2 {
+		fmt.Fprintf(os.Stderr, "Error: argument review-rounds must be between 0 and 2\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *sectionsFile == "" && *title == "" {
 		fmt.Fprintf(os.Stderr, "Error: argument title is required\n")
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	if *description == "" {
+	if *sectionsFile == "" && *description == "" {
 		fmt.Fprintf(os.Stderr, "Error: argument description is required\n")
 		flag.Usage()
 		os.Exit(1)
 	}
+
+	if *articles < 0 {
+		fmt.Fprintf(os.Stderr, "Error: argument articles must not be negative\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *cacheTTL < 0 {
+		fmt.Fprintf(os.Stderr, "Error: argument cache-ttl must not be negative\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *clearCache && *cacheDir != "" {
+		if err := newspaper.ClearResearchCache(*cacheDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+			os.Exit(1)
+		}
+	}
+
 	// Create request object
 	request := models.ContentRequest{
 		Body: map[string]any{
-			"days_back":           *daysBack,
-			"max_length":          *maxLength,
-			"section_title":       *title,
-			"section_description": *description,
+			"days_back":                  *daysBack,
+			"max_length":                 *maxLength,
+			"section_title":              *title,
+			"section_description":        *description,
+			"include_sources":            *sources,
+			"research_depth":             *depth,
+			"research_cache_bypass":      *noCache,
+			"research_judgment":          *judge,
+			"fact_check":                 *factCheck,
+			"date_strictness":            *dateStrictness,
+			"number_check":               *checkNumbers,
+			"copy_threshold":             *copyThreshold,
+			"review_rounds":              *reviewRounds,
+			"condense_trimmed":           *condense,
+			"min_fill":                   *minFill,
+			"articles_per_section":       *articles,
+			"retry_blocked_research":     *retryBlocked,
+			"substitute_blocked_stories": *substituteBlocked,
+			"allowed_domains":            *allow,
+			"blocked_domains":            *block,
+			"preferred_outlets":          *prefer,
 		},
 	}
 
+	if *sectionsFile != "" {
+		sections, err := readSections(*sectionsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+			os.Exit(1)
+		}
+
+		request.Body["sections"] = sections
+	}
+
 	ctx := context.Background()
 
-	generator, err := generators.Create("newspaper", nil)
+	generator, err := generators.Create("newspaper", generators.Config{
+		"research_cache_dir": *cacheDir,
+		"research_cache_ttl": cacheTTL.String(),
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
 		os.Exit(1)
@@ -67,3 +154,29 @@ func main() {
 
 	os.Exit(0)
 }
+
+// readSections reads a JSON list of section objects, each with the keys of
+// the 'sections' request key.
+func readSections(path string) ([]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sections: %w", err)
+	}
+
+	var sections []any
+
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("failed to parse sections: %w", err)
+	}
+
+	return sections, nil
+}
+
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "newspaper-assistant", "research")
+}
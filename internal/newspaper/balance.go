@@ -0,0 +1,277 @@
+package newspaper
+
+import (
+	"github.com/schraf/assistant/pkg/models"
+)
+
+// editGroup is the articles of one section along with the limits the editor
+// must keep them within.
+type editGroup struct {
+	section string
+	// items are the indexes of the articles of the section.
+	items []int
+	// minCount and maxCount bound the number of articles kept.
+	minCount int
+	maxCount int
+	// minLength and maxLength bound the length of the articles kept.
+	minLength int
+	maxLength int
+}
+
+// sectionGroups groups the articles by section, in order of first
+// appearance, with the limits of each section. Minimums are clamped to what
+// the section can provide and are left out entirely unless strict is set.
+func sectionGroups(articles []Article, doc models.Document, capacity int, strict bool) []editGroup {
+	var groups []editGroup
+	bySection := map[string]int{}
+
+	for index, article := range articles {
+		group, ok := bySection[article.Section.Title]
+		if !ok {
+			group = len(groups)
+			bySection[article.Section.Title] = group
+
+			section := article.Section
+			groups = append(groups, editGroup{
+				section:   section.Title,
+				maxCount:  section.MaxArticles,
+				maxLength: capacity,
+			})
+
+			if section.MaxShare > 0 {
+				groups[group].maxLength = int(section.MaxShare * float64(capacity))
+			}
+
+			if strict {
+				groups[group].minCount = section.MinArticles
+				groups[group].minLength = int(section.MinShare * float64(capacity))
+			}
+		}
+
+		groups[group].items = append(groups[group].items, index)
+	}
+
+	for index := range groups {
+		group := &groups[index]
+		total := 0
+
+		for _, item := range group.items {
+			total += sectionLength(doc.Sections[item])
+		}
+
+		if group.maxCount <= 0 || group.maxCount > len(group.items) {
+			group.maxCount = len(group.items)
+		}
+
+		group.minCount = min(group.minCount, group.maxCount)
+		group.minLength = min(group.minLength, total, group.maxLength)
+	}
+
+	return groups
+}
+
+// exceedsGroups reports whether the whole edition breaks the maximums of
+// any section, so the editor has to cut even when it fits the max length.
+func exceedsGroups(groups []editGroup, doc models.Document) bool {
+	for _, group := range groups {
+		total := 0
+
+		for _, item := range group.items {
+			total += sectionLength(doc.Sections[item])
+		}
+
+		if len(group.items) > group.maxCount || total > group.maxLength {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectOptions solves the multiple-choice knapsack of articles: it picks at
+// most one option of each article, whole or trimmed, so the options have the
+// greatest total worth that fits within the capacity while every section
+// keeps within its limits, preferring the fuller selection when worth ties.
+// It returns the chosen option of each article, or -1 for articles that are
+// cut, and false when the limits of the sections cannot all be met.
+//
+// The articles of each section are solved on their own for every count and
+// length, and the sections are then combined as a group knapsack.
+func selectOptions(options [][]editOption, scores []int, groups []editGroup, capacity int) ([]int, bool) {
+	unitSize := lengthUnitSize(capacity)
+	units := max(capacity, 0) / unitSize
+
+	weight := func(option editOption) int {
+		return (option.length + unitSize - 1) / unitSize
+	}
+
+	type best struct {
+		worth  int
+		length int
+		ok     bool
+	}
+
+	better := func(a, b best) bool {
+		return a.ok && (!b.ok || a.worth > b.worth || (a.worth == b.worth && a.length > b.length))
+	}
+
+	// total[unit] is the best selection of the sections so far that uses
+	// exactly unit length units.
+	total := make([]best, units+1)
+	total[0].ok = true
+
+	// picks[group][item][count][unit] is the option of the item chosen for
+	// the best selection of count articles using exactly unit length units.
+	picks := make([][][][]int, len(groups))
+
+	// counts[group][unit] and spent[group][unit] are the number of articles
+	// and length units the group contributes to total[unit].
+	counts := make([][]int, len(groups))
+	spent := make([][]int, len(groups))
+
+	for group, current := range groups {
+		table := make([][]best, len(current.items)+1)
+
+		for count := range table {
+			table[count] = make([]best, units+1)
+		}
+
+		table[0][0].ok = true
+		picks[group] = make([][][]int, len(current.items))
+
+		for item, index := range current.items {
+			next := make([][]best, len(table))
+			picks[group][item] = make([][]int, len(table))
+
+			for count := range table {
+				next[count] = append([]best(nil), table[count]...)
+				picks[group][item][count] = make([]int, units+1)
+
+				for unit := range picks[group][item][count] {
+					picks[group][item][count][unit] = -1
+				}
+			}
+
+			for count := 1; count <= item+1; count++ {
+				for choice, option := range options[index] {
+					optionWeight := weight(option)
+
+					for unit := optionWeight; unit <= units; unit++ {
+						previous := table[count-1][unit-optionWeight]
+						if !previous.ok {
+							continue
+						}
+
+						candidate := best{
+							worth:  previous.worth + scores[index]*option.worth,
+							length: previous.length + option.length,
+							ok:     true,
+						}
+
+						if better(candidate, next[count][unit]) {
+							next[count][unit] = candidate
+							picks[group][item][count][unit] = choice
+						}
+					}
+				}
+			}
+
+			table = next
+		}
+
+		// groupBest[unit] is the best selection of the group within its
+		// limits that uses exactly unit length units.
+		groupBest := make([]best, units+1)
+		groupCount := make([]int, units+1)
+
+		for count := current.minCount; count <= current.maxCount; count++ {
+			for unit, candidate := range table[count] {
+				if candidate.length < current.minLength || candidate.length > current.maxLength {
+					continue
+				}
+
+				if better(candidate, groupBest[unit]) {
+					groupBest[unit] = candidate
+					groupCount[unit] = count
+				}
+			}
+		}
+
+		merged := make([]best, units+1)
+		counts[group] = make([]int, units+1)
+		spent[group] = make([]int, units+1)
+
+		for groupUnit, candidate := range groupBest {
+			if !candidate.ok {
+				continue
+			}
+
+			for unit := groupUnit; unit <= units; unit++ {
+				previous := total[unit-groupUnit]
+				if !previous.ok {
+					continue
+				}
+
+				combined := best{
+					worth:  previous.worth + candidate.worth,
+					length: previous.length + candidate.length,
+					ok:     true,
+				}
+
+				if better(combined, merged[unit]) {
+					merged[unit] = combined
+					counts[group][unit] = groupCount[groupUnit]
+					spent[group][unit] = groupUnit
+				}
+			}
+		}
+
+		total = merged
+	}
+
+	choices := make([]int, len(options))
+
+	for index := range choices {
+		choices[index] = -1
+	}
+
+	bestUnit := -1
+
+	for unit, candidate := range total {
+		if bestUnit < 0 || better(candidate, total[bestUnit]) {
+			bestUnit = unit
+		}
+	}
+
+	if bestUnit < 0 || !total[bestUnit].ok {
+		return choices, false
+	}
+
+	unit := bestUnit
+
+	for group := len(groups) - 1; group >= 0; group-- {
+		count := counts[group][unit]
+		groupUnit := spent[group][unit]
+		unit -= groupUnit
+
+		for item := len(groups[group].items) - 1; item >= 0 && count > 0; item-- {
+			choice := picks[group][item][count][groupUnit]
+			if choice < 0 {
+				continue
+			}
+
+			index := groups[group].items[item]
+			choices[index] = choice
+			groupUnit -= weight(options[index][choice])
+			count--
+		}
+	}
+
+	return choices, true
+}
+
+// lengthUnitSize is the size of the length units the capacity is divided into
+// when selecting options, in characters.
+func lengthUnitSize(capacity int) int {
+	return max(lengthUnit, (capacity+maxLengthUnits-1)/maxLengthUnits)
+}
@@ -0,0 +1,126 @@
+package newspaper
+
+import (
+	"context"
+	"log/slog"
+)
+
+const (
+	// documentOverhead reserves room for the document title and author.
+	documentOverhead = 100
+
+	// minArticleLength and maxArticleLength bound the length budgeted for
+	// a single article body, in characters.
+	minArticleLength = 800
+	maxArticleLength = 8000
+
+	// minTargetLength is the shortest length budgeted for an article body,
+	// in characters, when the max length leaves too little room for the
+	// articles; the editor cuts what does not fit.
+	minTargetLength = 300
+
+	// paragraphLength is the typical length of a paragraph, in characters.
+	paragraphLength = 600
+
+	// structureLength estimates the length of the dek, byline and key points
+	// of an article, in characters.
+	structureLength = 400
+)
+
+// BudgetArticles divides the max length of the newspaper across the
+// articles by importance and sets the target length of each, so the edition
+// lands near the max length without throwing away finished articles.
+func BudgetArticles(ctx context.Context, articles []Article) (*[]Article, error) {
+	budgeted := append([]Article(nil), articles...)
+
+	maxLength := optionsFrom(ctx).MaxLength
+	available := maxLength - documentOverhead
+	totalImportance := 0
+
+	for _, article := range budgeted {
+		available -= articleOverhead(ctx, article)
+		totalImportance += max(article.Importance, minImportance)
+	}
+
+	// The layout adds a front page. The articles are not written yet, so
+	// the teasers of the front page stories are budgeted at their longest.
+	if frontPage := frontPageLength(budgeted); frontPage > 0 {
+		available -= frontPage + min(len(budgeted), 1+maxSecondaryStories)*(len(teaserSeparator)+maxTeaserLength)
+	}
+
+	if len(budgeted) == 0 {
+		return &budgeted, nil
+	}
+
+	// Articles whose share falls below the floor get the floor, and the rest
+	// of the length is shared by the others, so the targets never add up to
+	// more than is available. When there is too little room for even that,
+	// every article gets the shortest target rather than none, which
+	// synthesis would take as a full-length article.
+	floor := max(min(minArticleLength, available/len(budgeted)), minTargetLength)
+	floored := make([]bool, len(budgeted))
+	remaining := available
+
+	for changed := true; changed; {
+		changed = false
+
+		for index, article := range budgeted {
+			if floored[index] {
+				continue
+			}
+
+			if remaining*max(article.Importance, minImportance)/totalImportance < floor {
+				floored[index] = true
+				remaining -= floor
+				totalImportance -= max(article.Importance, minImportance)
+				changed = true
+			}
+		}
+	}
+
+	for index := range budgeted {
+		share := floor
+		if !floored[index] {
+			share = remaining * max(budgeted[index].Importance, minImportance) / totalImportance
+		}
+
+		budgeted[index].TargetLength = min(share, budgeted[index].Format.maxLength())
+
+		slog.Info("budgeted_article",
+			slog.String("section", budgeted[index].Section.Title),
+			slog.String("headline", budgeted[index].Headline),
+			slog.Int("importance", budgeted[index].Importance),
+			slog.Int("target_length", budgeted[index].TargetLength),
+		)
+	}
+
+	slog.Info("budgeting_finished",
+		slog.Int("articles", len(budgeted)),
+		slog.Int("available", available),
+		slog.Int("max_length", maxLength),
+	)
+
+	return &budgeted, nil
+}
+
+// articleOverhead is the length an article adds to the document besides its
+// body, such as its headline, dek, key points and list of sources.
+func articleOverhead(ctx context.Context, article Article) int {
+	overhead := len(article.Headline) + structureLength
+
+	if optionsFrom(ctx).IncludeSources && len(article.Sources) > 0 {
+		overhead += len(sourcesHeading)
+
+		for index, source := range article.Sources {
+			overhead += len(sourceCitation(index+1, source))
+		}
+	}
+
+	return overhead
+}
+
+// targetParagraphs is the number of paragraphs an article of the target
+// length should have.
+func targetParagraphs(targetLength int) int {
+	return max(2, (targetLength+paragraphLength/2)/paragraphLength)
+}
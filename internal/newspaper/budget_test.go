@@ -0,0 +1,92 @@
+package newspaper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plannedArticles plans one article per importance, in a single section,
+// with single-letter headlines.
+func plannedArticles(format ArticleFormat, importances ...int) []Article {
+	articles := make([]Article, len(importances))
+
+	for index, importance := range importances {
+		articles[index] = Article{
+			Section:    Section{Title: "S"},
+			Headline:   string(rune('A' + index)),
+			Format:     format,
+			Importance: importance,
+		}
+	}
+
+	return articles
+}
+
+func TestBudgetArticles(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxLength int
+		articles  []Article
+		targets   []int
+	}{
+		{
+			name:      "single article takes the whole length",
+			maxLength: 5000,
+			articles:  plannedArticles(FormatNews, 5),
+			targets:   []int{4499},
+		},
+		{
+			name:      "single article is capped",
+			maxLength: 20000,
+			articles:  plannedArticles(FormatNews, 5),
+			targets:   []int{maxArticleLength},
+		},
+		{
+			name:      "shares follow importance above the floor",
+			maxLength: 6000,
+			articles:  plannedArticles(FormatNews, 9, 1),
+			targets:   []int{3901, minArticleLength},
+		},
+		{
+			name:      "briefs are capped",
+			maxLength: 6000,
+			articles:  append(plannedArticles(FormatBrief, 9), plannedArticles(FormatNews, 1)...),
+			targets:   []int{briefMaxLength, minArticleLength},
+		},
+		{
+			name:      "floor shrinks for many articles on a small max length",
+			maxLength: 6000,
+			articles:  plannedArticles(FormatNews, 5, 5, 5, 5, 5, 5),
+			targets:   []int{457, 457, 457, 457, 457, 457},
+		},
+		{
+			name:      "floors never add up to more than is available",
+			maxLength: 4000,
+			articles:  plannedArticles(FormatNews, 10, 1, 1, 1),
+			targets:   []int{389, 388, 388, 388},
+		},
+		{
+			name:      "shortest target when the overhead fills the max length",
+			maxLength: 4000,
+			articles:  plannedArticles(FormatNews, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5),
+			targets:   []int{300, 300, 300, 300, 300, 300, 300, 300, 300, 300},
+		},
+	}
+
+	for _, test := range tests {
+		ctx := withOptions(context.Background(), NewspaperOptions{MaxLength: test.maxLength})
+
+		budgeted, err := BudgetArticles(ctx, test.articles)
+		require.NoError(t, err, test.name)
+
+		var targets []int
+		for _, article := range *budgeted {
+			targets = append(targets, article.TargetLength)
+		}
+
+		assert.Equal(t, test.targets, targets, test.name)
+	}
+}
@@ -0,0 +1,192 @@
+package newspaper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// researchCacheExt is the file extension of research cache entries.
+const researchCacheExt = ".research.json"
+
+// researchPromptVersion identifies the research prompts in use. Any change to
+// the prompts produces a new version and so invalidates cached research.
+var researchPromptVersion = promptVersion(
+	ResearchSystemPrompt,
+	ResearchPrompt,
+	ResearchFactualSystemPrompt,
+	ResearchFactualPrompt,
+	ResearchGapsPrompt,
+	ResearchFollowUpPrompt,
+	ResearchFactsPrompt,
+)
+
+type researchCacheEntry struct {
+	CreatedAt time.Time `json:"created_at"`
+	Research  string    `json:"research"`
+	Facts     []Fact    `json:"facts"`
+	Sources   []Source  `json:"sources"`
+}
+
+func promptVersion(prompts ...string) string {
+	hash := sha256.New()
+
+	for _, prompt := range prompts {
+		hash.Write([]byte(prompt))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// researchCacheKey returns the content address of the research for an article.
+func researchCacheKey(ctx context.Context, article Article) string {
+	key, _ := json.Marshal(map[string]any{
+		"version":        researchPromptVersion,
+		"section":        article.Section.Title,
+		"description":    article.Section.Description,
+		"headline":       article.Headline,
+		"summary":        article.Summary,
+		"date_range":     dateRangeString(ctx),
+		"research_depth": optionsFrom(ctx).ResearchDepth,
+		"source_policy":  sourcePolicy(ctx, article.Section),
+	})
+
+	hash := sha256.Sum256(key)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func researchCachePath(ctx context.Context, article Article) string {
+	return filepath.Join(optionsFrom(ctx).ResearchCacheDir, researchCacheKey(ctx, article)+researchCacheExt)
+}
+
+// loadCachedResearch fills in the research of an article from the cache. It
+// returns false when the cache is disabled or has no fresh entry.
+func loadCachedResearch(ctx context.Context, article *Article) bool {
+	options := optionsFrom(ctx)
+	if options.ResearchCacheDir == "" {
+		return false
+	}
+
+	data, err := os.ReadFile(researchCachePath(ctx, *article))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("research_cache_read_failed",
+				slog.String("headline", article.Headline),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		return false
+	}
+
+	var entry researchCacheEntry
+
+	if err := json.Unmarshal(data, &entry); err != nil {
+		slog.Warn("research_cache_unmarshal_failed",
+			slog.String("headline", article.Headline),
+			slog.String("error", err.Error()),
+		)
+
+		return false
+	}
+
+	if options.ResearchCacheTTL > 0 && time.Since(entry.CreatedAt) > options.ResearchCacheTTL {
+		return false
+	}
+
+	article.Research = entry.Research
+	article.Facts = entry.Facts
+	article.Sources = entry.Sources
+
+	return true
+}
+
+// storeCachedResearch writes the research of an article to the cache.
+func storeCachedResearch(ctx context.Context, article Article) {
+	options := optionsFrom(ctx)
+	if options.ResearchCacheDir == "" {
+		return
+	}
+
+	if err := writeCacheEntry(researchCachePath(ctx, article), researchCacheEntry{
+		CreatedAt: time.Now().UTC(),
+		Research:  article.Research,
+		Facts:     article.Facts,
+		Sources:   article.Sources,
+	}); err != nil {
+		slog.Warn("research_cache_write_failed",
+			slog.String("headline", article.Headline),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func writeCacheEntry(path string, entry researchCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".research-*")
+	if err != nil {
+		return fmt.Errorf("create cache file: %w", err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return fmt.Errorf("write cache file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("close cache file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("rename cache file: %w", err)
+	}
+
+	return nil
+}
+
+// ClearResearchCache removes all cached research entries from the directory.
+// Other files in the directory are left untouched.
+func ClearResearchCache(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("read research cache: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), researchCacheExt) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("remove research cache entry: %w", err)
+		}
+	}
+
+	return nil
+}
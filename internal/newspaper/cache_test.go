@@ -0,0 +1,172 @@
+package newspaper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResearchCacheKey(t *testing.T) {
+	options := NewspaperOptions{DaysBack: 1, ResearchDepth: 1}
+	article := Article{
+		Section:  Section{Title: "Local", Description: "City news"},
+		Headline: "Council passes budget",
+		Summary:  "The council passed the budget on Monday.",
+	}
+
+	key := researchCacheKey(withOptions(context.Background(), options), article)
+
+	// The same article and options always have the same key.
+	assert.Equal(t, key, researchCacheKey(withOptions(context.Background(), options), article))
+
+	tests := []struct {
+		name   string
+		change func(options *NewspaperOptions, article *Article)
+	}{
+		{"section", func(options *NewspaperOptions, article *Article) { article.Section.Title = "World" }},
+		{"description", func(options *NewspaperOptions, article *Article) { article.Section.Description = "Town news" }},
+		{"headline", func(options *NewspaperOptions, article *Article) { article.Headline = "Council rejects budget" }},
+		{"summary", func(options *NewspaperOptions, article *Article) { article.Summary = "The council met on Monday." }},
+		{"date range", func(options *NewspaperOptions, article *Article) { options.DaysBack = 2 }},
+		{"research depth", func(options *NewspaperOptions, article *Article) { options.ResearchDepth = 2 }},
+		{"edition source policy", func(options *NewspaperOptions, article *Article) {
+			options.SourcePolicy.BlockedDomains = []string{"example.com"}
+		}},
+		{"section source policy", func(options *NewspaperOptions, article *Article) {
+			article.Section.SourcePolicy.AllowedDomains = []string{"example.com"}
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changedOptions, changedArticle := options, article
+			test.change(&changedOptions, &changedArticle)
+
+			assert.NotEqual(t, key, researchCacheKey(withOptions(context.Background(), changedOptions), changedArticle))
+		})
+	}
+}
+
+func TestResearchCache(t *testing.T) {
+	dir := t.TempDir()
+	ctx := withOptions(context.Background(), NewspaperOptions{DaysBack: 1, ResearchCacheDir: dir})
+
+	article := Article{
+		Section:  Section{Title: "Local"},
+		Headline: "Council passes budget",
+		Research: "The council passed the budget on Monday.",
+		Facts:    []Fact{{Statement: "The council passed the budget.", SourceURL: "https://example.com/budget"}},
+		Sources:  []Source{{Name: "Example", URL: "https://example.com/budget"}},
+	}
+
+	cached := Article{Section: article.Section, Headline: article.Headline}
+	assert.False(t, loadCachedResearch(ctx, &cached))
+
+	storeCachedResearch(ctx, article)
+
+	require.True(t, loadCachedResearch(ctx, &cached))
+	assert.Equal(t, article.Research, cached.Research)
+	assert.Equal(t, article.Facts, cached.Facts)
+	assert.Equal(t, article.Sources, cached.Sources)
+
+	// Without a cache directory nothing is read.
+	assert.False(t, loadCachedResearch(withOptions(context.Background(), NewspaperOptions{DaysBack: 1}), &cached))
+}
+
+func TestResearchCacheTTL(t *testing.T) {
+	dir := t.TempDir()
+	article := Article{Section: Section{Title: "Local"}, Headline: "Council passes budget"}
+
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		fresh bool
+	}{
+		{"fresh", 3 * time.Hour, true},
+		{"expired", time.Hour, false},
+		{"never expires", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := withOptions(context.Background(), NewspaperOptions{DaysBack: 1, ResearchCacheDir: dir, ResearchCacheTTL: test.ttl})
+
+			require.NoError(t, writeCacheEntry(researchCachePath(ctx, article), researchCacheEntry{
+				CreatedAt: time.Now().UTC().Add(-2 * time.Hour),
+				Research:  "The council passed the budget on Monday.",
+			}))
+
+			cached := article
+			assert.Equal(t, test.fresh, loadCachedResearch(ctx, &cached))
+		})
+	}
+}
+
+func TestClearResearchCache(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"a" + researchCacheExt, "b" + researchCacheExt, "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644))
+	}
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "c"+researchCacheExt), 0o755))
+	require.NoError(t, ClearResearchCache(dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var names []string
+
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	assert.Equal(t, []string{"c" + researchCacheExt, "notes.txt"}, names)
+
+	// A missing cache directory is already clear.
+	assert.NoError(t, ClearResearchCache(filepath.Join(dir, "missing")))
+}
+
+func TestResearchArticleUsesCache(t *testing.T) {
+	dir := t.TempDir()
+	article := Article{Section: Section{Title: "Local"}, Headline: "Council passes budget"}
+
+	assistant := &stubAssistant{
+		answer: func(persona, request string) (string, error) {
+			return "The council passed the budget on Monday.", nil
+		},
+		structured: func(persona, request string) (string, error) {
+			return `[]`, nil
+		},
+	}
+
+	research := func(options NewspaperOptions) *Article {
+		researched, err := ResearchArticle(withAssistant(withOptions(context.Background(), options), assistant), article)
+		require.NoError(t, err)
+		require.Equal(t, StatusResearched, researched.Status)
+
+		return researched
+	}
+
+	research(NewspaperOptions{DaysBack: 1, ResearchCacheDir: dir})
+	assert.Equal(t, 1, assistant.asks)
+
+	cached := research(NewspaperOptions{DaysBack: 1, ResearchCacheDir: dir})
+	assert.Equal(t, 1, assistant.asks)
+	assert.Equal(t, "The council passed the budget on Monday.", cached.Research)
+	assert.Equal(t, "loaded from research cache", cached.StatusReason)
+
+	// A different research depth is a different cache entry.
+	research(NewspaperOptions{DaysBack: 1, ResearchCacheDir: dir, ResearchDepth: 1})
+	assert.Equal(t, 2, assistant.asks)
+
+	// Without a cache directory, as when the cache is bypassed, the story is
+	// researched again.
+	research(NewspaperOptions{DaysBack: 1})
+	assert.Equal(t, 3, assistant.asks)
+}
@@ -0,0 +1,95 @@
+package newspaper
+
+import "sync"
+
+// candidatePool holds the planned stories of each section that were not
+// selected for the edition, in plan order, so they can stand in for stories
+// that fail. It also records the stories they replaced.
+type candidatePool struct {
+	mu         sync.Mutex
+	candidates map[string][]Article
+	replaced   []Article
+}
+
+func newCandidatePool() *candidatePool {
+	return &candidatePool{
+		candidates: map[string][]Article{},
+	}
+}
+
+// add appends reserve candidates for a section.
+func (p *candidatePool) add(section string, articles []Article) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.candidates[section] = append(p.candidates[section], articles...)
+}
+
+// next removes and returns the best remaining candidate for a section.
+func (p *candidatePool) next(section string) (Article, bool) {
+	if p == nil {
+		return Article{}, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	candidates := p.candidates[section]
+	if len(candidates) == 0 {
+		return Article{}, false
+	}
+
+	p.candidates[section] = candidates[1:]
+
+	return candidates[0], true
+}
+
+// replace records an article that a candidate was substituted for.
+func (p *candidatePool) replace(article Article) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.replaced = append(p.replaced, article)
+}
+
+// replacedArticles returns the articles that candidates were substituted for.
+func (p *candidatePool) replacedArticles() []Article {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Article(nil), p.replaced...)
+}
+
+// reservedArticles returns the candidates that were never taken, section
+// by section in edition order, marked as held in reserve.
+func (p *candidatePool) reservedArticles(sections []Section) []Article {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var reserved []Article
+
+	for _, section := range sections {
+		for _, article := range p.candidates[section.Title] {
+			article.SetStatus(StagePlan, StatusReserved, "held in reserve and never needed")
+			reserved = append(reserved, article)
+		}
+	}
+
+	return reserved
+}
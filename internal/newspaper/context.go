@@ -12,6 +12,8 @@ type contextKey int
 
 var assistantContextKey contextKey = 0
 var optionsContextKey contextKey = 1
+var candidatesContextKey contextKey = 2
+var sectionsContextKey contextKey = 3
 
 func withAssistant(ctx context.Context, assistant models.Assistant) context.Context {
 	return context.WithValue(ctx, assistantContextKey, assistant)
@@ -30,6 +32,24 @@ func optionsFrom(ctx context.Context) NewspaperOptions {
 	return options
 }
 
+func withCandidates(ctx context.Context, candidates *candidatePool) context.Context {
+	return context.WithValue(ctx, candidatesContextKey, candidates)
+}
+
+func candidatesFrom(ctx context.Context) *candidatePool {
+	candidates, _ := ctx.Value(candidatesContextKey).(*candidatePool)
+	return candidates
+}
+
+func withSections(ctx context.Context, sections []Section) context.Context {
+	return context.WithValue(ctx, sectionsContextKey, sections)
+}
+
+func sectionsFrom(ctx context.Context) []Section {
+	sections, _ := ctx.Value(sectionsContextKey).([]Section)
+	return sections
+}
+
 func ask(ctx context.Context, persona string, request string) (*string, error) {
 	assistant, ok := ctx.Value(assistantContextKey).(models.Assistant)
 	if !ok {
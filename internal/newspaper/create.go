@@ -8,13 +8,22 @@ import (
 	"github.com/schraf/pipeline"
 )
 
-func CreateNewspaper(ctx context.Context, assistant models.Assistant, section Section, options NewspaperOptions) (*models.Document, error) {
+func CreateNewspaper(ctx context.Context, assistant models.Assistant, sections []Section, options NewspaperOptions) (*Edition, error) {
 	//--===============================================================--
 	//--== CREATE PIPELINE
 	//--===============================================================--
 
 	ctx = withAssistant(ctx, assistant)
 	ctx = withOptions(ctx, options)
+
+	sections = append([]Section(nil), sections...)
+	for order := range sections {
+		sections[order].order = order
+	}
+
+	ctx = withSections(ctx, sections)
+	candidates := newCandidatePool()
+	ctx = withCandidates(ctx, candidates)
 	pipe, ctx := pipeline.WithPipeline(ctx)
 
 	//--===============================================================--
@@ -25,15 +34,17 @@ func CreateNewspaper(ctx context.Context, assistant models.Assistant, section Se
 	const capacity = 2
 
 	//--===============================================================--
-	//--== STAGE 0 : SOURCE NEWSPAPER SECTION
+	//--== STAGE 0 : SOURCE NEWSPAPER SECTIONS
 	//--===============================================================--
 
-	stage0 := make(chan Section, 1)
-	stage0 <- section
+	stage0 := make(chan Section, len(sections))
+	for _, section := range sections {
+		stage0 <- section
+	}
 	close(stage0)
 
 	//--===============================================================--
-	//--== STAGE 1 : PLAN ARTICLES FOR SECTION
+	//--== STAGE 1 : PLAN ARTICLES FOR EACH SECTION
 	//--===============================================================--
 
 	stage1 := make(chan []Article, capacity)
@@ -50,43 +61,88 @@ func CreateNewspaper(ctx context.Context, assistant models.Assistant, section Se
 	//--== STAGE 3 : RESEARCH EACH ARTICLE
 	//--===============================================================--
 
-	stage3 := make(chan Article, capacity)
-	pipeline.Transform(pipe, ResearchArticle, stage2, stage3)
+	stage3 := transformArticles(pipe, researchStages, stage2, capacity)
 
 	//--===============================================================--
-	//--== STAGE 4 : FILTER OUT ANY INVALID ARTICLES
+	//--== STAGE 4 : SPLIT OUT ANY DROPPED ARTICLES
 	//--===============================================================--
 
 	stage4 := make(chan Article, capacity)
-	pipeline.Filter(pipe, filterValidArticles, stage3, stage4)
+	dropped4 := make(chan Article, capacity)
+	pipeline.Split(pipe, routeArticle, stage3, stage4, dropped4)
+
+	//--===============================================================--
+	//--== STAGE 5 : AGGREGATE RESEARCHED ARTICLES
+	//--===============================================================--
+
+	stage5 := make(chan []Article, 1)
+	pipeline.Aggregate(pipe, stage4, stage5)
 
 	//--===============================================================--
-	//--== STAGE 5 : SYNTHESIZE THE RESEARCH
+	//--== STAGE 6 : BUDGET ARTICLE LENGTHS
 	//--===============================================================--
 
-	stage5 := make(chan Article, capacity)
-	pipeline.Transform(pipe, SynthesizeArticle, stage4, stage5)
+	stage6 := make(chan []Article, 1)
+	pipeline.Transform(pipe, BudgetArticles, stage5, stage6)
 
 	//--===============================================================--
-	//--== STAGE 6 : FILTER OUT ANY INVALID ARTICLES
+	//--== STAGE 7 : FLATTEN BUDGETED ARTICLES
 	//--===============================================================--
 
-	stage6 := make(chan Article, capacity)
-	pipeline.Filter(pipe, filterValidArticles, stage5, stage6)
+	stage7 := make(chan Article, capacity)
+	pipeline.Flatten(pipe, stage6, stage7)
 
 	//--===============================================================--
-	//--== STAGE 7 : AGGREGATE ALL ARTICLES
+	//--== STAGE 8 : WRITE AND CHECK EACH ARTICLE
 	//--===============================================================--
 
-	stage7 := make(chan []Article, 1)
-	pipeline.Aggregate(pipe, stage6, stage7)
+	stage8 := transformArticles(pipe, writingStages, stage7, capacity)
 
 	//--===============================================================--
-	//--== STAGE 8 : EDIT FINAL NEWSPAPER
+	//--== STAGE 9 : SPLIT OUT ANY DROPPED ARTICLES
+	//--===============================================================--
+
+	stage9 := make(chan Article, capacity)
+	dropped9 := make(chan Article, capacity)
+	pipeline.Split(pipe, routeArticle, stage8, stage9, dropped9)
+
+	//--===============================================================--
+	//--== STAGE 10 : AGGREGATE ALL ARTICLES
+	//--===============================================================--
+
+	stage10 := make(chan []Article, 1)
+	pipeline.Aggregate(pipe, stage9, stage10)
+
 	//--===============================================================--
+	//--== STAGE 11 : FILL UNDERLENGTH NEWSPAPER
+	//--===============================================================--
+
+	stage11 := make(chan []Article, 1)
+	pipeline.Transform(pipe, FillNewspaper, stage10, stage11)
+
+	//--===============================================================--
+	//--== STAGE 12 : EDIT FINAL NEWSPAPER
+	//--===============================================================--
+
+	stage12 := make(chan Edition, 1)
+	pipeline.Transform(pipe, EditNewspaper, stage11, stage12)
+
+	//--===============================================================--
+	//--== STAGE 13 : LAY OUT FRONT PAGE AND SECTIONS
+	//--===============================================================--
+
+	stage13 := make(chan Edition, 1)
+	pipeline.Transform(pipe, LayoutNewspaper, stage12, stage13)
+
+	//--===============================================================--
+	//--== COLLECT DROPPED ARTICLES
+	//--===============================================================--
+
+	dropped := make(chan Article, capacity)
+	pipeline.FanIn(pipe, dropped, dropped4, dropped9)
 
-	stage8 := make(chan models.Document, 1)
-	pipeline.Transform(pipe, EditNewspaper, stage7, stage8)
+	droppedAll := make(chan []Article, 1)
+	pipeline.Aggregate(pipe, dropped, droppedAll)
 
 	//--===============================================================--
 	//--== GET NEWSPAPER
@@ -96,7 +152,9 @@ func CreateNewspaper(ctx context.Context, assistant models.Assistant, section Se
 		return nil, fmt.Errorf("failed during newspaper pipeline: %w", err)
 	}
 
-	newspaper := <-stage8
+	newspaper := <-stage13
+	newspaper.Dropped = append(append(candidates.replacedArticles(), <-droppedAll...), newspaper.Dropped...)
+	newspaper.Dropped = append(newspaper.Dropped, candidates.reservedArticles(sections)...)
 
 	return &newspaper, nil
 }
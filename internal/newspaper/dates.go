@@ -0,0 +1,378 @@
+package newspaper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateStrictness is what the date check stage does with sentences that
+// mention dates before the Date Range.
+type DateStrictness string
+
+const (
+	DateStrictnessOff    DateStrictness = "off"
+	DateStrictnessWarn   DateStrictness = "warn"
+	DateStrictnessRevise DateStrictness = "revise"
+	DateStrictnessDrop   DateStrictness = "drop"
+)
+
+const (
+	checkResearchDates = "research-dates"
+	checkArticleDates  = "article-dates"
+)
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+const monthPattern = `(January|February|March|April|May|June|July|August|September|October|November|December|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sept|Sep|Oct|Nov|Dec)\.?`
+
+var (
+	isoDatePattern        = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	monthDayPattern       = regexp.MustCompile(`\b` + monthPattern + `\s+(\d{1,2})(?:st|nd|rd|th)?\b(?:,?\s+(\d{4})\b)?`)
+	dayMonthPattern       = regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?` + monthPattern + `(?:,?\s+(\d{4})\b)?`)
+	monthYearPattern      = regexp.MustCompile(`\b` + monthPattern + `\s+((?:19|20)\d{2})\b`)
+	yearPattern           = regexp.MustCompile(`(?i)\b(?:in|since|during|of)\s+((?:19|20)\d{2})\b`)
+	relativeDayPattern    = regexp.MustCompile(`(?i)\b(yesterday|(\d+|one|two|three|four|five|six|seven|eight|nine|ten) days ago)\b`)
+	lastWeekdayPattern    = regexp.MustCompile(`(?i)\blast (sunday|monday|tuesday|wednesday|thursday|friday|saturday)\b`)
+	abbreviationPattern   = regexp.MustCompile(`(?i)(?:^|\s)(?:mr|mrs|ms|dr|st|sen|rep|gov|gen|lt|col|sgt|jr|sr|inc|corp|co|ltd|vs|no|u\.s|u\.k|u\.n|jan|feb|mar|apr|jun|jul|aug|sep|sept|oct|nov|dec|[a-z])\.$`)
+	paragraphSplitPattern = regexp.MustCompile(`\n\s*\n`)
+)
+
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// CheckResearchDates looks for sentences in the research notes that mention
+// dates before the Date Range. They are logged, or stripped from the notes
+// when the date strictness is revise or drop.
+func CheckResearchDates(ctx context.Context, article Article) (*Article, error) {
+	strictness := optionsFrom(ctx).DateStrictness
+	if !article.Status.Active() || strictness == DateStrictnessOff || strictness == "" {
+		return &article, nil
+	}
+
+	start, end := dateRange(ctx)
+	research, flagged := stripOutOfRange(article.Research, start, end)
+
+	if len(flagged) == 0 {
+		article.AddCheck(checkResearchDates, false, nil)
+		return &article, nil
+	}
+
+	slog.Warn("research_dates_out_of_range",
+		slog.String("section", article.Section.Title),
+		slog.String("headline", article.Headline),
+		slog.String("strictness", string(strictness)),
+		slog.Any("sentences", flagged),
+	)
+
+	if strictness == DateStrictnessWarn {
+		article.AddCheck(checkResearchDates, false, flagged)
+		return &article, nil
+	}
+
+	article.Research = research
+	article.AddCheck(checkResearchDates, true, nil)
+
+	if strings.TrimSpace(article.Research) == "" {
+		article.SetStatus(StageDateCheck, StatusInsufficient, "all research notes fall outside the date range")
+	}
+
+	return &article, nil
+}
+
+// CheckArticleDates looks for sentences in the article body, dek and key
+// points that mention dates before the Date Range. Depending on the date
+// strictness they are logged, stripped from the article, or the article is
+// rejected.
+func CheckArticleDates(ctx context.Context, article Article) (*Article, error) {
+	strictness := optionsFrom(ctx).DateStrictness
+	if !article.Status.Active() || strictness == DateStrictnessOff || strictness == "" {
+		return &article, nil
+	}
+
+	start, end := dateRange(ctx)
+	body, flagged := stripOutOfRange(article.Body, start, end)
+	dek, flaggedDek := stripOutOfRange(article.Dek, start, end)
+	flagged = append(flagged, flaggedDek...)
+
+	var keyPoints []string
+
+	for _, point := range article.KeyPoints {
+		point, flaggedPoint := stripOutOfRange(point, start, end)
+		flagged = append(flagged, flaggedPoint...)
+
+		if strings.TrimSpace(point) != "" {
+			keyPoints = append(keyPoints, point)
+		}
+	}
+
+	if len(flagged) == 0 {
+		article.AddCheck(checkArticleDates, false, nil)
+		return &article, nil
+	}
+
+	slog.Warn("article_dates_out_of_range",
+		slog.String("section", article.Section.Title),
+		slog.String("headline", article.Headline),
+		slog.String("strictness", string(strictness)),
+		slog.Any("sentences", flagged),
+	)
+
+	switch strictness {
+	case DateStrictnessRevise:
+		article.Body = body
+		article.Dek = dek
+		article.KeyPoints = keyPoints
+		article.AddCheck(checkArticleDates, true, nil)
+
+		if strings.TrimSpace(article.Body) == "" {
+			article.SetStatus(StageDateCheck, StatusRejected, "every sentence falls outside the date range")
+		}
+	case DateStrictnessDrop:
+		article.AddCheck(checkArticleDates, false, flagged)
+		article.SetStatus(StageDateCheck, StatusRejected, fmt.Sprintf("%d sentences fall outside the date range", len(flagged)))
+	default:
+		article.AddCheck(checkArticleDates, false, flagged)
+	}
+
+	return &article, nil
+}
+
+// stripOutOfRange removes every sentence that mentions a date before start,
+// keeping the paragraph and line structure of the text. It returns the
+// remaining text and the removed sentences.
+func stripOutOfRange(text string, start time.Time, end time.Time) (string, []string) {
+	return stripSentences(text, func(sentence string) (string, bool) {
+		date, ok := outOfRangeDate(sentence, start, end)
+		if !ok {
+			return "", false
+		}
+
+		return fmt.Sprintf("%s (%s)", sentence, date), true
+	})
+}
+
+// stripSentences removes every sentence the match function matches, keeping
+// the paragraph and line structure of the text. It returns the remaining
+// text and the descriptions the match function gave the removed sentences.
+func stripSentences(text string, match func(sentence string) (string, bool)) (string, []string) {
+	var flagged []string
+	var paragraphs []string
+
+	for _, paragraph := range paragraphSplitPattern.Split(text, -1) {
+		var lines []string
+
+		for _, line := range strings.Split(paragraph, "\n") {
+			var kept []string
+
+			for _, sentence := range splitSentences(line) {
+				if description, ok := match(sentence); ok {
+					flagged = append(flagged, description)
+					continue
+				}
+
+				kept = append(kept, sentence)
+			}
+
+			if len(kept) > 0 {
+				lines = append(lines, strings.Join(kept, " "))
+			}
+		}
+
+		if len(lines) > 0 {
+			paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+		}
+	}
+
+	if len(flagged) == 0 {
+		return text, nil
+	}
+
+	return strings.Join(paragraphs, "\n\n"), flagged
+}
+
+// splitSentences splits a line of text into sentences, without splitting on
+// the periods of common abbreviations and initials.
+func splitSentences(line string) []string {
+	var sentences []string
+	begin := 0
+
+	for index := 0; index < len(line); index++ {
+		if line[index] != '.' && line[index] != '!' && line[index] != '?' {
+			continue
+		}
+
+		end := index + 1
+		for end < len(line) && strings.ContainsRune(`"')”’`, rune(line[end])) {
+			end++
+		}
+
+		if end < len(line) && line[end] != ' ' {
+			continue
+		}
+
+		if line[index] == '.' && abbreviationPattern.MatchString(line[begin:index+1]) {
+			continue
+		}
+
+		if sentence := strings.TrimSpace(line[begin:end]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+
+		begin = end
+	}
+
+	if sentence := strings.TrimSpace(line[begin:]); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
+
+// outOfRangeDate returns the first date mentioned in the sentence that falls
+// before start. Dates after end are forward looking (scheduled votes,
+// hearings, releases) and are not treated as out of range. Dates without a
+// year resolve to their occurrence nearest to end, and
+// relative dates ("yesterday", "last Tuesday") are relative to end.
+func outOfRangeDate(sentence string, start time.Time, end time.Time) (string, bool) {
+	startDay := truncateDay(start)
+	endDay := truncateDay(end)
+
+	before := func(date time.Time) bool {
+		return date.Before(startDay)
+	}
+
+	for _, match := range isoDatePattern.FindAllStringSubmatch(sentence, -1) {
+		if date, err := time.Parse("2006-01-02", match[0]); err == nil && before(date) {
+			return match[0], true
+		}
+	}
+
+	for _, match := range monthDayPattern.FindAllStringSubmatch(sentence, -1) {
+		if date, ok := resolveMonthDay(match[1], match[2], match[3], endDay); ok && before(date) {
+			return match[0], true
+		}
+	}
+
+	for _, match := range dayMonthPattern.FindAllStringSubmatch(sentence, -1) {
+		if date, ok := resolveMonthDay(match[2], match[1], match[3], endDay); ok && before(date) {
+			return match[0], true
+		}
+	}
+
+	for _, match := range monthYearPattern.FindAllStringSubmatch(sentence, -1) {
+		month, ok := months[strings.ToLower(match[1])]
+		if !ok {
+			continue
+		}
+
+		if year, err := strconv.Atoi(match[2]); err == nil && before(time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)) {
+			return match[0], true
+		}
+	}
+
+	for _, match := range yearPattern.FindAllStringSubmatch(sentence, -1) {
+		if year, err := strconv.Atoi(match[1]); err == nil && year < startDay.Year() {
+			return match[0], true
+		}
+	}
+
+	for _, match := range relativeDayPattern.FindAllStringSubmatch(sentence, -1) {
+		days := 1
+
+		if match[2] != "" {
+			if count, err := strconv.Atoi(match[2]); err == nil {
+				days = count
+			} else {
+				days = numberWords[strings.ToLower(match[2])]
+			}
+		}
+
+		if before(endDay.AddDate(0, 0, -days)) {
+			return match[0], true
+		}
+	}
+
+	for _, match := range lastWeekdayPattern.FindAllStringSubmatch(sentence, -1) {
+		weekday := weekdays[strings.ToLower(match[1])]
+
+		days := (int(endDay.Weekday()) - int(weekday) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+
+		if before(endDay.AddDate(0, 0, -days)) {
+			return match[0], true
+		}
+	}
+
+	return "", false
+}
+
+// resolveMonthDay resolves a month, day and optional year to a date. Without
+// a year, the occurrence nearest to end is used.
+func resolveMonthDay(monthName string, dayText string, yearText string, end time.Time) (time.Time, bool) {
+	month, ok := months[strings.ToLower(strings.TrimSuffix(monthName, "."))]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	day, err := strconv.Atoi(dayText)
+	if err != nil || day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+
+	if yearText != "" {
+		year, err := strconv.Atoi(yearText)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), true
+	}
+
+	date := time.Date(end.Year(), month, day, 0, 0, 0, 0, time.UTC)
+
+	switch {
+	case date.Sub(end) > 183*24*time.Hour:
+		date = date.AddDate(-1, 0, 0)
+	case end.Sub(date) > 183*24*time.Hour:
+		date = date.AddDate(1, 0, 0)
+	}
+
+	return date, true
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,96 @@
+package newspaper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutOfRangeDate(t *testing.T) {
+	// Thursday, March 12 2026 back through Thursday, March 5 2026.
+	end := time.Date(2026, time.March, 12, 15, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, 0, -7)
+
+	tests := []struct {
+		sentence string
+		out      bool
+	}{
+		{"The vote took place on 2026-03-10.", false},
+		{"The law was first passed on 2025-11-02.", true},
+		{"Officials met on March 6 to discuss the plan.", false},
+		{"The plant closed on March 3, shortly after the storm.", true},
+		{"The deal was signed on Feb. 27, 2026 in Paris.", true},
+		{"The hearing is scheduled for April 2.", false},
+		{"Protests began on 4 March in the capital.", true},
+		{"Unemployment has fallen since 2019.", true},
+		{"The agency reported the figures yesterday.", false},
+		{"The fire started ten days ago.", true},
+		{"The minister resigned last Tuesday.", false},
+		{"The company was founded in January 2024.", true},
+		{"Shares rose 4 percent on Monday.", false},
+	}
+
+	for _, test := range tests {
+		_, out := outOfRangeDate(test.sentence, start, end)
+		assert.Equal(t, test.out, out, test.sentence)
+	}
+}
+
+func TestStripOutOfRange(t *testing.T) {
+	end := time.Date(2026, time.March, 12, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, 0, -7)
+
+	text := "Mr. Smith spoke on March 10. The program began in 2018. It has grown since.\n\n" +
+		"The board met on Jan. 5, 2026. Nothing else happened."
+
+	stripped, flagged := stripOutOfRange(text, start, end)
+
+	assert.Equal(t, "Mr. Smith spoke on March 10. It has grown since.\n\nNothing else happened.", stripped)
+	assert.Len(t, flagged, 2)
+}
+
+func TestCheckArticleDatesChecksDekAndKeyPoints(t *testing.T) {
+	tests := []struct {
+		strictness DateStrictness
+		status     ArticleStatus
+		dek        string
+		keyPoints  []string
+	}{
+		{DateStrictnessWarn, StatusSynthesized, "Unemployment has fallen since 2019.", []string{"The council approved the plan.", "The program began in 2018."}},
+		{DateStrictnessRevise, StatusSynthesized, "", []string{"The council approved the plan."}},
+		{DateStrictnessDrop, StatusRejected, "Unemployment has fallen since 2019.", []string{"The council approved the plan.", "The program began in 2018."}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.strictness), func(t *testing.T) {
+			ctx := withOptions(context.Background(), NewspaperOptions{DaysBack: 7, DateStrictness: test.strictness})
+
+			article := Article{
+				Dek:       "Unemployment has fallen since 2019.",
+				KeyPoints: []string{"The council approved the plan.", "The program began in 2018."},
+				Body:      "The council approved the plan this week.",
+			}
+			article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+			checked, err := CheckArticleDates(ctx, article)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.status, checked.Status)
+			assert.Equal(t, test.dek, checked.Dek)
+			assert.Equal(t, test.keyPoints, checked.KeyPoints)
+			assert.Equal(t, article.Body, checked.Body)
+
+			require.Len(t, checked.Checks, 1)
+
+			if test.strictness == DateStrictnessRevise {
+				assert.True(t, checked.Checks[0].Revised)
+				assert.Empty(t, checked.Checks[0].Issues)
+			} else {
+				assert.Len(t, checked.Checks[0].Issues, 2)
+			}
+		})
+	}
+}
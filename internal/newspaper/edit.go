@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"math/rand"
+	"sort"
 	"strings"
 
 	"github.com/schraf/assistant/pkg/models"
@@ -13,8 +13,9 @@ import (
 
 const (
 	EditSystemPrompt = `
-		You are an expert newspaper editor. Your task is to trim down the newspaper
-		to fit within a specific length, while retaining the most important articles.
+		You are an expert newspaper editor. Your task is to rank the articles
+		of the whole edition, so the most important lead it and are kept when
+		the newspaper has to be trimmed down to fit within a specific length.
 		`
 
 	EditPrompt = `
@@ -28,110 +29,436 @@ const (
 		{{.Articles}}
 
 		## Task
-		Review the list of articles and their lengths. Decide which single article
-		to remove to help bring the total length closer to the maximum, while
-		sacrificing the least amount of important content. The list of articles is 
-		provided in a markdown table format.
+		Score every article in the list by its importance to readers of the
+		whole edition from 1 (minor) to 10 (major), comparing articles across
+		sections. The most important article leads the edition, and when the
+		newspaper is longer than the max length the least important content is
+		shortened or removed. Score articles on their own merit rather than
+		their length; lengths are only provided for context. The list of
+		articles is provided in a markdown table format.
 		`
 )
 
-func EditNewspaper(ctx context.Context, articles []Article) (*models.Document, error) {
+const (
+	// lengthUnit is the finest granularity of article lengths when selecting
+	// the articles that fit the max length, in characters. Lengths are
+	// rounded up to it, so the selection never exceeds the max length.
+	lengthUnit = 10
+
+	// maxLengthUnits bounds the number of length units the max length is
+	// divided into, which keeps selection fast for long editions.
+	maxLengthUnits = 2000
+)
+
+type articleScore struct {
+	Index      int `json:"index"`
+	Importance int `json:"importance"`
+}
+
+// EditNewspaper lays the articles out in a document and, when it is longer
+// than the max length or a section has more than its share, has the editor
+// score every article in a single call and keeps the set of articles with the
+// greatest total importance that fits while honoring the limits of each
+// section, trimming articles where that saves a whole story from being cut.
+// The editor's scores replace the planner importance of the articles, and
+// multi-section editions are scored even when they fit, so the layout ranks
+// stories of different sections against each other. When the editor cannot
+// score the articles, whole articles are cut in a fixed order by their
+// planner importance and the rest are trimmed to fit.
+func EditNewspaper(ctx context.Context, articles []Article) (*Edition, error) {
+	// Articles that were dropped while filling the edition are passed on.
+	var dropped []Article
+
+	for _, article := range articles {
+		if !article.Status.Active() {
+			dropped = append(dropped, article)
+		}
+	}
+
+	// Articles arrive in the order their pipeline finished, so put them in
+	// plan order to make editing the same inputs give the same edition.
+	articles = activeArticles(articles)
+
+	sort.SliceStable(articles, func(i, j int) bool {
+		if articles[i].Section.order != articles[j].Section.order {
+			return articles[i].Section.order < articles[j].Section.order
+		}
+
+		if articles[i].Rank != articles[j].Rank {
+			return articles[i].Rank < articles[j].Rank
+		}
+
+		return articles[i].Headline < articles[j].Headline
+	})
+
 	doc := models.Document{}
 
 	for _, article := range articles {
-		doc.AddSection(article.Headline, article.Body)
+		addArticle(ctx, &doc, article)
 	}
 
 	maxLength := optionsFrom(ctx).MaxLength
 
+	// The layout adds a front page, so the articles have to fit in what it
+	// leaves of the max length.
+	frontPage := frontPageLength(articles)
+	capacity := max(maxLength-frontPage, 0)
+
 	slog.Info("editing_start",
 		slog.Int("articles", len(articles)),
 		slog.Int("length", doc.Length()),
 		slog.Int("max_length", maxLength),
+		slog.Int("front_page_length", frontPage),
 	)
 
-	for doc.Length() > maxLength {
-		if len(doc.Sections) == 0 {
-			break
-		}
+	groups := sectionGroups(articles, doc, capacity, true)
+	fits := doc.Length() <= capacity && !exceedsGroups(groups, doc)
+	lengths := make([]int, len(doc.Sections))
 
-		var articlesTable strings.Builder
-		articlesTable.WriteString("| Index | Headline | Length |\n")
-		articlesTable.WriteString("|---|---|---|\n")
+	for index, section := range doc.Sections {
+		lengths[index] = sectionLength(section)
+	}
 
-		for index, section := range doc.Sections {
-			length := 0
+	// Planner importance only ranks the stories of one section, so the
+	// editor also scores multi-section editions that fit, for the layout to
+	// pick the lead story across sections. The scores replace the planner
+	// importance of every article.
+	var scores []int
 
-			for _, paragraph := range section.Paragraphs {
-				length += len(paragraph)
-			}
+	if !fits || len(groups) > 1 {
+		var err error
 
-			articlesTable.WriteString(fmt.Sprintf("| %d | %s | %d |\n", index, section.Title, length))
+		if scores, err = scoreArticles(ctx, doc, articles, lengths); err != nil {
+			slog.Warn("edit_ask_failed",
+				slog.String("error", err.Error()),
+			)
 		}
 
-		prompt, err := BuildPrompt(EditPrompt, PromptArgs{
-			"MaxLength":     maxLength,
-			"CurrentLength": doc.Length(),
-			"Articles":      articlesTable.String(),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("edit newspaper prompt error: %w", err)
+		for index := range scores {
+			articles[index].Importance = scores[index]
 		}
+	}
 
-		schema := map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"index": map[string]any{
-					"type":        "integer",
-					"description": "The index of the article to remove from the list.",
-				},
-			},
-			"required": []string{"index"},
+	if fits {
+		slog.Info("editing_finished",
+			slog.Int("articles", len(doc.Sections)),
+			slog.Int("length", doc.Length()),
+			slog.Int("max_length", maxLength),
+		)
+
+		return &Edition{
+			Document: doc,
+			Articles: articles,
+			Dropped:  dropped,
+		}, nil
+	}
+
+	options := make([][]editOption, len(articles))
+
+	for index, section := range doc.Sections {
+		options[index] = trimOptions(ctx, articles[index], section)
+	}
+
+	var choices []int
+	var ok bool
+
+	if scores == nil {
+		choices, ok = trimByImportance(articles, options, groups, capacity)
+	} else {
+		choices, ok = selectOptions(options, scores, groups, capacity)
+	}
+
+	if !ok {
+		slog.Warn("edit_section_minimums_infeasible",
+			slog.Int("max_length", maxLength),
+		)
+
+		loose := sectionGroups(articles, doc, capacity, false)
+
+		if scores == nil {
+			choices, _ = trimByImportance(articles, options, loose, capacity)
+		} else {
+			choices, _ = selectOptions(options, scores, loose, capacity)
 		}
+	}
 
-		var sectionToRemove struct{ Index int }
+	reason := fmt.Sprintf("removed to fit max length %d", maxLength)
+	if doc.Length() <= capacity {
+		reason = "removed to fit the article limits of its section"
+	}
 
-		responseJson, err := structuredAsk(ctx, EditSystemPrompt, *prompt, schema)
-		if err != nil {
-			slog.Warn("edit_ask_failed",
-				slog.String("error", err.Error()),
+	edited := models.Document{Title: doc.Title, Author: doc.Author}
+	var kept []Article
+	cut := dropped
+
+	for index, article := range articles {
+		choice := choices[index]
+
+		if choice < 0 {
+			article.SetStatus(StageEdit, StatusCutByEditor, reason)
+			cut = append(cut, article)
+
+			slog.Info("removed article",
+				slog.String("removed_article_title", article.Headline),
+				slog.Int("length", lengths[index]),
 			)
 
-			sectionToRemove.Index = rand.Intn(len(doc.Sections))
-		} else {
-			if err := json.Unmarshal(responseJson, &sectionToRemove); err != nil {
-				slog.Warn("edit_ask_unmarshal_failed",
-					slog.String("error", err.Error()),
-				)
-
-				sectionToRemove.Index = rand.Intn(len(doc.Sections))
-			} else {
-				if sectionToRemove.Index < 0 || sectionToRemove.Index >= len(doc.Sections) {
-					slog.Warn("edit_ask_index_invalid",
-						slog.Int("index", sectionToRemove.Index),
-					)
-
-					sectionToRemove.Index = rand.Intn(len(doc.Sections))
-				}
-			}
+			continue
 		}
 
-		removedArticleTitle := doc.Sections[sectionToRemove.Index].Title
-		doc.Sections = append(doc.Sections[:sectionToRemove.Index], doc.Sections[sectionToRemove.Index+1:]...)
+		option := options[index][choice]
 
-		slog.Info("removed article",
-			slog.String("removed_article_title", removedArticleTitle),
-			slog.Int("remaining_articles", len(doc.Sections)),
-			slog.Int("length", doc.Length()),
-			slog.Int("max_length", maxLength),
-		)
+		if choice > 0 {
+			slog.Info("trimmed article",
+				slog.String("trimmed_article_title", article.Headline),
+				slog.Int("length", lengths[index]),
+				slog.Int("trimmed_length", option.length),
+			)
+
+			if optionsFrom(ctx).CondenseTrimmed {
+				option = condenseArticle(ctx, article, option)
+			}
+		}
+
+		edited.Sections = append(edited.Sections, option.section)
+		kept = append(kept, option.article)
 	}
 
 	slog.Info("editing_finished",
-		slog.Int("articles", len(doc.Sections)),
-		slog.Int("length", doc.Length()),
+		slog.Int("articles", len(edited.Sections)),
+		slog.Int("length", edited.Length()),
 		slog.Int("max_length", maxLength),
 	)
 
-	return &doc, nil
+	return &Edition{
+		Document: edited,
+		Articles: kept,
+		Dropped:  cut,
+	}, nil
+}
+
+// scoreArticles asks the editor to score the importance of every article in
+// a single call. Articles the editor does not score keep the importance
+// given to them by the planner.
+func scoreArticles(ctx context.Context, doc models.Document, articles []Article, lengths []int) ([]int, error) {
+	var articlesTable strings.Builder
+	articlesTable.WriteString("| Index | Section | Headline | Format | Length |\n")
+	articlesTable.WriteString("|---|---|---|---|---|\n")
+
+	for index, article := range articles {
+		articlesTable.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %d |\n", index, article.Section.Title, doc.Sections[index].Title, article.Format, lengths[index]))
+	}
+
+	prompt, err := BuildPrompt(EditPrompt, PromptArgs{
+		"MaxLength":     optionsFrom(ctx).MaxLength,
+		"CurrentLength": doc.Length(),
+		"Articles":      articlesTable.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("edit newspaper prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"articles": map[string]any{
+				"type":        "array",
+				"description": "importance score of every article in the list",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"index": map[string]any{
+							"type":        "integer",
+							"description": "The index of the article in the list.",
+						},
+						"importance": map[string]any{
+							"type":        "integer",
+							"description": "importance of the article from 1 (minor) to 10 (major)",
+						},
+					},
+					"required": []string{"index", "importance"},
+				},
+			},
+		},
+		"required": []string{"articles"},
+	}
+
+	responseJson, err := structuredAsk(ctx, EditSystemPrompt, *prompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("edit structured ask: %w", err)
+	}
+
+	var result struct {
+		Articles []articleScore
+	}
+
+	if err := json.Unmarshal(responseJson, &result); err != nil {
+		return nil, fmt.Errorf("edit unmarshal json: %w", err)
+	}
+
+	scores := make([]int, len(articles))
+
+	for index, article := range articles {
+		scores[index] = max(article.Importance, minImportance)
+	}
+
+	for _, score := range result.Articles {
+		if score.Index < 0 || score.Index >= len(articles) {
+			slog.Warn("edit_ask_index_invalid",
+				slog.Int("index", score.Index),
+			)
+
+			continue
+		}
+
+		scores[score.Index] = min(max(score.Importance, minImportance), maxImportance)
+	}
+
+	return scores, nil
+}
+
+// trimByImportance is the fallback when the editor cannot score the
+// articles. Whole articles are cut in the order of cutByImportance until the
+// rest fit when trimmed as far as they can be, and the kept articles are then
+// trimmed no more than they need to be, by planner importance.
+func trimByImportance(articles []Article, options [][]editOption, groups []editGroup, capacity int) ([]int, bool) {
+	unitSize := lengthUnitSize(capacity)
+	shortest := make([]int, len(articles))
+	scores := make([]int, len(articles))
+
+	for index, article := range articles {
+		// Lengths are rounded up to whole units as when selecting options,
+		// so the kept articles are sure to fit.
+		length := options[index][len(options[index])-1].length
+		shortest[index] = (length + unitSize - 1) / unitSize * unitSize
+		scores[index] = max(article.Importance, minImportance)
+	}
+
+	cuts := cutByImportance(articles, shortest, groups, capacity)
+	kept := make([]editGroup, len(groups))
+
+	for group, current := range groups {
+		kept[group] = current
+		kept[group].items = nil
+
+		for _, item := range current.items {
+			if cuts[item] == 0 {
+				kept[group].items = append(kept[group].items, item)
+			}
+		}
+
+		// Every article that survived the cuts is kept.
+		kept[group].minCount = len(kept[group].items)
+		kept[group].maxCount = len(kept[group].items)
+	}
+
+	return selectOptions(options, scores, kept, capacity)
+}
+
+// cutByImportance cuts whole articles in a fixed order until every section
+// is within its maximums and the rest fit within the capacity: the least
+// important first, then the longest, then the latest in the list. Articles of
+// sections at their minimum count or share are cut only when nothing else is
+// left to cut, so the lead story is the last to go. It returns 0 for articles
+// that are kept and -1 for articles that are cut.
+func cutByImportance(articles []Article, lengths []int, groups []editGroup, capacity int) []int {
+	choices := make([]int, len(articles))
+	total := 0
+
+	for _, length := range lengths {
+		total += length
+	}
+
+	kept := make([]int, len(groups))
+	keptLength := make([]int, len(groups))
+	groupOf := make([]int, len(articles))
+
+	for group, current := range groups {
+		for _, item := range current.items {
+			groupOf[item] = group
+			kept[group]++
+			keptLength[group] += lengths[item]
+		}
+	}
+
+	// before reports whether article a should be cut before article b.
+	before := func(a, b int) bool {
+		if articles[a].Importance != articles[b].Importance {
+			return articles[a].Importance < articles[b].Importance
+		}
+
+		if lengths[a] != lengths[b] {
+			return lengths[a] > lengths[b]
+		}
+
+		return a > b
+	}
+
+	// next picks the next article to cut among those the filter allows.
+	next := func(allowed func(item int) bool) int {
+		pick := -1
+
+		for item := range articles {
+			if choices[item] < 0 || !allowed(item) {
+				continue
+			}
+
+			if pick < 0 || before(item, pick) {
+				pick = item
+			}
+		}
+
+		return pick
+	}
+
+	cut := func(item int) {
+		group := groupOf[item]
+		choices[item] = -1
+		total -= lengths[item]
+		kept[group]--
+		keptLength[group] -= lengths[item]
+	}
+
+	for {
+		over := next(func(item int) bool {
+			group := groupOf[item]
+			return kept[group] > groups[group].maxCount || keptLength[group] > groups[group].maxLength
+		})
+
+		if over < 0 {
+			break
+		}
+
+		cut(over)
+	}
+
+	for total > capacity {
+		item := next(func(item int) bool {
+			group := groupOf[item]
+			return kept[group] > groups[group].minCount && keptLength[group]-lengths[item] >= groups[group].minLength
+		})
+
+		if item < 0 {
+			item = next(func(int) bool { return true })
+		}
+
+		if item < 0 {
+			break
+		}
+
+		cut(item)
+	}
+
+	return choices
+}
+
+// sectionLength is the length a section adds to the document.
+func sectionLength(section models.DocumentSection) int {
+	length := len(section.Title)
+
+	for _, paragraph := range section.Paragraphs {
+		length += len(paragraph)
+	}
+
+	return length
 }
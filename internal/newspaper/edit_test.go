@@ -0,0 +1,208 @@
+package newspaper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wholeOptions lists a single whole option for each article length.
+func wholeOptions(lengths ...int) [][]editOption {
+	options := make([][]editOption, len(lengths))
+
+	for index, length := range lengths {
+		options[index] = []editOption{{length: length, worth: wholeWorth}}
+	}
+
+	return options
+}
+
+// selectWhole selects among the options of articles of a single section
+// without limits.
+func selectWhole(options [][]editOption, scores []int, capacity int) []int {
+	group := editGroup{maxCount: len(options), maxLength: capacity}
+
+	for index := range options {
+		group.items = append(group.items, index)
+	}
+
+	choices, _ := selectOptions(options, scores, []editGroup{group}, capacity)
+	return choices
+}
+
+func TestSelectOptions(t *testing.T) {
+	// Removing the single longest article would leave room to spare, but
+	// keeping it with the short article scores higher.
+	options := wholeOptions(3000, 2000, 2000, 1000)
+	scores := []int{9, 5, 5, 3}
+
+	assert.Equal(t, []int{0, -1, -1, 0}, selectWhole(options, scores, 4000))
+	assert.Equal(t, []int{0, 0, 0, 0}, selectWhole(options, scores, 8000))
+	assert.Equal(t, []int{-1, -1, -1, -1}, selectWhole(options, scores, 500))
+}
+
+func TestSelectOptionsPrefersFullerEdition(t *testing.T) {
+	assert.Equal(t, []int{-1, 0}, selectWhole(wholeOptions(1000, 2500), []int{5, 5}, 3000))
+}
+
+func TestSelectOptionsTrimsBeforeCutting(t *testing.T) {
+	options := wholeOptions(2000, 2000)
+	options[1] = append(options[1], editOption{length: 1800, worth: 95})
+
+	assert.Equal(t, []int{0, 1}, selectWhole(options, []int{5, 5}, 3900))
+}
+
+func TestSelectOptionsHonorsSectionLimits(t *testing.T) {
+	options := wholeOptions(2000, 2000, 2000, 1500)
+	scores := []int{9, 8, 7, 2}
+
+	// Without limits the world section would be cut entirely.
+	groups := []editGroup{
+		{section: "US", items: []int{0, 1, 2}, maxCount: 3, maxLength: 6000},
+		{section: "World", items: []int{3}, minCount: 1, maxCount: 1, maxLength: 6000},
+	}
+
+	choices, ok := selectOptions(options, scores, groups, 6000)
+	require.True(t, ok)
+	assert.Equal(t, []int{0, 0, -1, 0}, choices)
+
+	groups[0].maxCount = 1
+	choices, ok = selectOptions(options, scores, groups, 6000)
+	require.True(t, ok)
+	assert.Equal(t, []int{0, -1, -1, 0}, choices)
+
+	groups[1].minLength = 2000
+	_, ok = selectOptions(options, scores, groups, 6000)
+	assert.False(t, ok)
+}
+
+func TestTrimOptions(t *testing.T) {
+	ctx := withOptions(context.Background(), NewspaperOptions{})
+	paragraph := strings.Repeat("The port reopened on Monday. ", 4)
+
+	article := Article{
+		Headline: "Port reopens",
+		Format:   FormatNews,
+		Body:     strings.Join([]string{paragraph, paragraph, paragraph, paragraph, paragraph}, "\n\n"),
+	}
+
+	options := trimOptions(ctx, article, renderArticle(ctx, article))
+	require.Len(t, options, 3)
+
+	assert.Equal(t, wholeWorth, options[0].worth)
+	// Trims keep at least half of the body.
+	assert.Equal(t, 4, len(options[1].section.Paragraphs))
+	assert.Equal(t, 3, len(options[2].section.Paragraphs))
+	assert.Less(t, options[2].length, options[1].length)
+	assert.Less(t, options[2].worth, options[1].worth)
+
+	// Briefs, timelines and Q&As lose what matters if their last
+	// paragraphs are dropped.
+	for _, format := range []ArticleFormat{FormatBrief, FormatTimeline, FormatQA} {
+		article.Format = format
+		assert.Len(t, trimOptions(ctx, article, renderArticle(ctx, article)), 1, format)
+	}
+
+	article.Format = FormatExplainer
+	assert.Len(t, trimOptions(ctx, article, renderArticle(ctx, article)), 3)
+}
+
+func TestCutByImportance(t *testing.T) {
+	articles := []Article{
+		{Headline: "Lead", Importance: 9},
+		{Headline: "Short", Importance: 4},
+		{Headline: "Long", Importance: 4},
+		{Headline: "Late", Importance: 4},
+	}
+	lengths := []int{3000, 1000, 2000, 2000}
+	groups := []editGroup{{items: []int{0, 1, 2, 3}, maxCount: 4, maxLength: 8000}}
+
+	// The least important go first, the longest of them first, and the long
+	// and late articles tie on length, so the later one goes first.
+	assert.Equal(t, []int{0, 0, 0, -1}, cutByImportance(articles, lengths, groups, 6000))
+	assert.Equal(t, []int{0, 0, -1, -1}, cutByImportance(articles, lengths, groups, 4000))
+	assert.Equal(t, []int{0, -1, -1, -1}, cutByImportance(articles, lengths, groups, 3000))
+	assert.Equal(t, []int{-1, -1, -1, -1}, cutByImportance(articles, lengths, groups, 2000))
+}
+
+func TestCutByImportanceHonorsSectionLimits(t *testing.T) {
+	articles := []Article{
+		{Headline: "US lead", Importance: 9},
+		{Headline: "US second", Importance: 7},
+		{Headline: "US third", Importance: 6},
+		{Headline: "World lead", Importance: 3},
+	}
+	lengths := []int{2000, 2000, 2000, 2000}
+	groups := []editGroup{
+		{items: []int{0, 1, 2}, maxCount: 2, maxLength: 8000},
+		{items: []int{3}, minCount: 1, maxCount: 1, maxLength: 8000},
+	}
+
+	assert.Equal(t, []int{0, -1, -1, 0}, cutByImportance(articles, lengths, groups, 5000))
+}
+
+func TestTrimByImportance(t *testing.T) {
+	articles := []Article{
+		{Headline: "Short", Importance: 4},
+		{Headline: "Long", Importance: 4},
+		{Headline: "Late", Importance: 4},
+	}
+	groups := []editGroup{{items: []int{0, 1, 2}, maxCount: 3, maxLength: 10000}}
+
+	// Unlike the editor's selection, which prefers the fuller edition, the
+	// fallback cuts the longest article of equal importance first.
+	options := wholeOptions(1000, 2000, 1000)
+	choices, ok := trimByImportance(articles, options, groups, 3000)
+	require.True(t, ok)
+	assert.Equal(t, []int{0, -1, 0}, choices)
+
+	// With equal lengths the latest goes first.
+	choices, ok = trimByImportance(articles, wholeOptions(1000, 1000, 1000), groups, 2000)
+	require.True(t, ok)
+	assert.Equal(t, []int{0, 0, -1}, choices)
+
+	// Articles that can be trimmed to fit are trimmed rather than cut.
+	options[1] = append(options[1], editOption{length: 1000, worth: 75})
+	choices, ok = trimByImportance(articles, options, groups, 3000)
+	require.True(t, ok)
+	assert.Equal(t, []int{0, 1, 0}, choices)
+}
+
+func TestEditNewspaperWithoutEditor(t *testing.T) {
+	paragraph := strings.Repeat("The port reopened on Monday. ", 4)
+	body := strings.Join([]string{paragraph, paragraph, paragraph, paragraph, paragraph}, "\n\n")
+	section := Section{Title: "Business"}
+
+	articles := []Article{
+		{Status: StatusSynthesized, Section: section, Headline: "Port reopens", Format: FormatNews, Importance: 8, Rank: 0, Body: body},
+		{Status: StatusSynthesized, Section: section, Headline: "Strike ends", Format: FormatNews, Importance: 3, Rank: 1, Body: body},
+	}
+
+	ctx := withOptions(context.Background(), NewspaperOptions{})
+	length := editionLength(ctx, articles) + frontPageLength(articles)
+
+	// Without an assistant the editor cannot score the articles, so the
+	// planner importance decides and the less important article is trimmed
+	// rather than cut.
+	ctx = withOptions(context.Background(), NewspaperOptions{MaxLength: length - len(paragraph)})
+
+	edition, err := EditNewspaper(ctx, articles)
+	require.NoError(t, err)
+	require.Len(t, edition.Articles, 2)
+	assert.Empty(t, edition.Dropped)
+	assert.Equal(t, body, edition.Articles[0].Body)
+	assert.Less(t, len(edition.Articles[1].Body), len(body))
+
+	// With room for only one article the less important one is cut.
+	ctx = withOptions(context.Background(), NewspaperOptions{MaxLength: length / 2})
+
+	edition, err = EditNewspaper(ctx, articles)
+	require.NoError(t, err)
+	require.Len(t, edition.Articles, 1)
+	assert.Equal(t, "Port reopens", edition.Articles[0].Headline)
+	require.Len(t, edition.Dropped, 1)
+	assert.Equal(t, StatusCutByEditor, edition.Dropped[0].Status)
+}
@@ -0,0 +1,194 @@
+package newspaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+const (
+	FactCheckSystemPrompt = `
+		You are an expert newspaper fact checker. Your task is to verify that
+		every claim in an article is supported by the research notes it was
+		written from.
+		`
+
+	FactCheckPrompt = `
+		## Research Notes (source material)
+		{{.Research}}
+
+		## Article
+		{{.Body}}
+
+		## Task
+		Extract every checkable claim from the article: numbers and figures,
+		names of people and organizations, dates, and quotes. For each claim,
+		check whether the research notes support it exactly. A claim is not
+		supported if it is missing from the research notes, contradicts them,
+		or changes a number, name, date or quote. Copy the sentence of the
+		article that contains each claim.
+		`
+
+	FactCheckReviseTask = `
+		Revise the article so that every claim is supported by the research
+		notes. Correct unsupported claims to match the research notes, or
+		remove the sentences containing them when the research notes do not
+		cover them.
+		`
+)
+
+// checkFactCheck names the fact check in article check results.
+const checkFactCheck = "fact-check"
+
+type claimCheck struct {
+	Claim     string `json:"claim"`
+	Kind      string `json:"kind"`
+	Sentence  string `json:"sentence"`
+	Supported bool   `json:"supported"`
+}
+
+// FactCheckArticle checks the claims of a synthesized article, its dek and
+// key points included, against its research notes. Depending on the fact
+// check mode, articles with unsupported claims are revised once or rejected.
+func FactCheckArticle(ctx context.Context, article Article) (*Article, error) {
+	mode := optionsFrom(ctx).FactCheck
+	if !article.Status.Active() || mode == FactCheckOff || mode == "" {
+		return &article, nil
+	}
+
+	issues, err := unsupportedClaims(ctx, article)
+	if err != nil {
+		slog.Warn("fact_check_failed",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.String("error", err.Error()),
+		)
+
+		return &article, nil
+	}
+
+	revised := false
+
+	if len(issues) > 0 && mode == FactCheckRevise {
+		slog.Info("fact_check_revising",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Any("claims", issues),
+		)
+
+		draft, err := reviseArticle(ctx, article, FactCheckReviseTask, issues)
+		if err != nil {
+			slog.Warn("fact_check_revise_failed",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			article = *draft
+			revised = true
+
+			issues, err = unsupportedClaims(ctx, article)
+			if err != nil {
+				slog.Warn("fact_check_failed",
+					slog.String("section", article.Section.Title),
+					slog.String("headline", article.Headline),
+					slog.String("error", err.Error()),
+				)
+
+				return &article, nil
+			}
+		}
+	}
+
+	article.AddCheck(checkFactCheck, revised, issues)
+
+	if len(issues) > 0 {
+		slog.Warn("fact_check_unsupported",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Any("claims", issues),
+		)
+
+		article.SetStatus(StageFactCheck, StatusRejected, fmt.Sprintf("%d claims not supported by research", len(issues)))
+		return &article, nil
+	}
+
+	slog.Info("fact_checked_article",
+		slog.String("section", article.Section.Title),
+		slog.String("headline", article.Headline),
+	)
+
+	return &article, nil
+}
+
+// unsupportedClaims returns a description of every claim in the article
+// text that the research notes do not support.
+func unsupportedClaims(ctx context.Context, article Article) ([]string, error) {
+	prompt, err := BuildPrompt(FactCheckPrompt, PromptArgs{
+		"Research": article.Research,
+		"Body":     article.text(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fact check prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"claims": map[string]any{
+				"type":        "array",
+				"description": "checkable claims made by the article",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"claim": map[string]any{
+							"type":        "string",
+							"description": "the claim",
+						},
+						"kind": map[string]any{
+							"type":        "string",
+							"enum":        []string{"number", "name", "date", "quote"},
+							"description": "the kind of claim",
+						},
+						"sentence": map[string]any{
+							"type":        "string",
+							"description": "the sentence of the article containing the claim",
+						},
+						"supported": map[string]any{
+							"type":        "boolean",
+							"description": "true if the research notes support the claim",
+						},
+					},
+					"required": []string{"claim", "kind", "sentence", "supported"},
+				},
+			},
+		},
+		"required": []string{"claims"},
+	}
+
+	responseJson, err := structuredAsk(ctx, FactCheckSystemPrompt, *prompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("fact check structured ask: %w", err)
+	}
+
+	var result struct {
+		Claims []claimCheck
+	}
+
+	if err := json.Unmarshal(responseJson, &result); err != nil {
+		return nil, fmt.Errorf("fact check unmarshal json: %w", err)
+	}
+
+	var issues []string
+
+	for _, claim := range result.Claims {
+		if claim.Supported {
+			continue
+		}
+
+		issues = append(issues, fmt.Sprintf("unsupported %s %q in sentence: %s", claim.Kind, claim.Claim, claim.Sentence))
+	}
+
+	return issues, nil
+}
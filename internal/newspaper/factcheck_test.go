@@ -0,0 +1,150 @@
+package newspaper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	supportedClaims   = `{"claims": [{"claim": "12", "kind": "number", "sentence": "The vote was 12 to 3.", "supported": true}]}`
+	unsupportedClaim  = `{"claims": [{"claim": "14", "kind": "number", "sentence": "The vote was 14 to 3.", "supported": false}]}`
+	unsupportedIssue  = `unsupported number "14" in sentence: The vote was 14 to 3.`
+	factCheckedBody   = "The council passed the budget. The vote was 14 to 3."
+	factRevisedBody   = "The council passed the budget. The vote was 12 to 3."
+	factCheckResearch = "The council passed the budget 12 to 3 on Monday."
+)
+
+// factCheckAssistant answers the fact checks in turn, failing once they run
+// out, and revises articles to factRevisedBody.
+func factCheckAssistant(checks ...string) *stubAssistant {
+	return &stubAssistant{
+		structured: func(persona, request string) (string, error) {
+			if persona != FactCheckSystemPrompt {
+				return `{"dek": "The council passed the budget.", "key_points": ["The vote was 12 to 3."], "paragraphs": ["` + factRevisedBody + `"]}`, nil
+			}
+
+			if len(checks) == 0 {
+				return "", assert.AnError
+			}
+
+			check := checks[0]
+			checks = checks[1:]
+
+			return check, nil
+		},
+	}
+}
+
+func TestFactCheckArticle(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           FactCheckMode
+		checks         []string
+		body           string
+		status         ArticleStatus
+		revised        bool
+		issues         []string
+		structuredAsks int
+	}{
+		{"off", FactCheckOff, nil, factCheckedBody, StatusSynthesized, false, nil, 0},
+		{"drop supported", FactCheckDrop, []string{supportedClaims}, factCheckedBody, StatusSynthesized, false, nil, 1},
+		{"drop unsupported", FactCheckDrop, []string{unsupportedClaim}, factCheckedBody, StatusRejected, false, []string{unsupportedIssue}, 1},
+		{"revise fixed", FactCheckRevise, []string{unsupportedClaim, supportedClaims}, factRevisedBody, StatusSynthesized, true, nil, 3},
+		{"revise still unsupported", FactCheckRevise, []string{unsupportedClaim, unsupportedClaim}, factRevisedBody, StatusRejected, true, []string{unsupportedIssue}, 3},
+		{"revise supported", FactCheckRevise, []string{supportedClaims}, factCheckedBody, StatusSynthesized, false, nil, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assistant := factCheckAssistant(test.checks...)
+			ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{FactCheck: test.mode}), assistant)
+
+			article := Article{Body: factCheckedBody, Dek: "The council passed the budget 14 to 3.", Research: factCheckResearch}
+			article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+			checked, err := FactCheckArticle(ctx, article)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.body, checked.Body)
+			assert.Equal(t, test.status, checked.Status)
+
+			if test.revised {
+				assert.Equal(t, "The council passed the budget.", checked.Dek)
+				assert.Equal(t, []string{"The vote was 12 to 3."}, checked.KeyPoints)
+			} else {
+				assert.Equal(t, article.Dek, checked.Dek)
+			}
+			assert.Equal(t, test.structuredAsks, assistant.structuredAsks)
+
+			if test.mode == FactCheckOff {
+				assert.Empty(t, checked.Checks)
+				return
+			}
+
+			require.Len(t, checked.Checks, 1)
+			assert.Equal(t, checkFactCheck, checked.Checks[0].Check)
+			assert.Equal(t, test.revised, checked.Checks[0].Revised)
+			assert.Equal(t, test.issues, checked.Checks[0].Issues)
+		})
+	}
+}
+
+func TestFactCheckArticlePassesThroughWhenCheckFails(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []string
+		body   string
+	}{
+		{"check fails", nil, factCheckedBody},
+		{"re-check fails", []string{unsupportedClaim}, factRevisedBody},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assistant := factCheckAssistant(test.checks...)
+			ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{FactCheck: FactCheckRevise}), assistant)
+
+			article := Article{Body: factCheckedBody, Research: factCheckResearch}
+			article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+			checked, err := FactCheckArticle(ctx, article)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.body, checked.Body)
+			assert.Equal(t, StatusSynthesized, checked.Status)
+			assert.Empty(t, checked.Checks)
+		})
+	}
+}
+
+func TestFactCheckArticleChecksDekAndKeyPoints(t *testing.T) {
+	var requests []string
+
+	assistant := &stubAssistant{
+		structured: func(persona, request string) (string, error) {
+			requests = append(requests, request)
+			return supportedClaims, nil
+		},
+	}
+
+	ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{FactCheck: FactCheckDrop}), assistant)
+
+	article := Article{
+		Dek:       "Budget passes with room to spare.",
+		KeyPoints: []string{"Three members voted against it."},
+		Body:      factCheckedBody,
+		Research:  factCheckResearch,
+	}
+	article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+	_, err := FactCheckArticle(ctx, article)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	assert.Contains(t, requests[0], "Budget passes with room to spare.")
+	assert.Contains(t, requests[0], "Three members voted against it.")
+	assert.Contains(t, requests[0], factCheckedBody)
+}
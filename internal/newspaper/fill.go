@@ -0,0 +1,219 @@
+package newspaper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/schraf/assistant/pkg/models"
+	"github.com/schraf/pipeline"
+)
+
+const (
+	// maxFillRounds is the most rounds of additional stories written to
+	// fill an edition that falls short of its minimum fill.
+	maxFillRounds = 2
+
+	// maxFillArticles is the most additional stories written per round.
+	maxFillArticles = 4
+)
+
+// FillNewspaper writes additional stories when the finished articles fall
+// short of the minimum fill of the edition. Stories come from the reserve
+// candidates of each section, or from planning more stories for a section
+// once its reserve is used up, and go through every article stage. Articles
+// that do not make it are passed on with their status, to be reported as
+// dropped.
+func FillNewspaper(ctx context.Context, articles []Article) (*[]Article, error) {
+	options := optionsFrom(ctx)
+	filled := append([]Article(nil), articles...)
+
+	if options.MinFill <= 0 {
+		return &filled, nil
+	}
+
+	target := int(options.MinFill * float64(options.MaxLength))
+	planned := map[string]bool{}
+
+	for round := 0; round < maxFillRounds; round++ {
+		length := editionLength(ctx, filled)
+		if length >= target {
+			break
+		}
+
+		candidates := fillCandidates(ctx, filled, target-length, planned)
+		if len(candidates) == 0 {
+			slog.Warn("fill_candidates_exhausted",
+				slog.Int("length", length),
+				slog.Int("target", target),
+			)
+
+			break
+		}
+
+		slog.Info("filling_newspaper",
+			slog.Int("round", round+1),
+			slog.Int("length", length),
+			slog.Int("target", target),
+			slog.Int("articles", len(candidates)),
+		)
+
+		written, err := writeArticles(ctx, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("fill newspaper error: %w", err)
+		}
+
+		filled = append(filled, written...)
+	}
+
+	slog.Info("filling_finished",
+		slog.Int("length", editionLength(ctx, filled)),
+		slog.Int("target", target),
+	)
+
+	return &filled, nil
+}
+
+// fillCandidates takes enough stories to cover the missing length, one
+// section at a time in edition order, and budgets them to share it.
+func fillCandidates(ctx context.Context, articles []Article, missing int, planned map[string]bool) []Article {
+	perArticle := structureLength + minArticleLength
+
+	if active := activeArticles(articles); len(active) > 0 {
+		perArticle = max(perArticle, editionLength(ctx, active)/len(active))
+	}
+
+	count := min(max((missing+perArticle-1)/perArticle, 1), maxFillArticles)
+	sections := sectionsFrom(ctx)
+	exhausted := map[string]bool{}
+	var candidates []Article
+
+	for len(candidates) < count && len(exhausted) < len(sections) {
+		for _, section := range sections {
+			if len(candidates) == count || exhausted[section.Title] {
+				continue
+			}
+
+			candidate, ok := nextCandidate(ctx, section, articles, planned)
+			if !ok {
+				exhausted[section.Title] = true
+				continue
+			}
+
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	for index := range candidates {
+		share := missing/len(candidates) - len(candidates[index].Headline) - structureLength
+		candidates[index].TargetLength = min(max(share, minArticleLength), candidates[index].Format.maxLength())
+	}
+
+	return candidates
+}
+
+// nextCandidate takes the next reserve story of a section, planning more
+// stories for the section the first time its reserve runs out.
+func nextCandidate(ctx context.Context, section Section, articles []Article, planned map[string]bool) (Article, bool) {
+	pool := candidatesFrom(ctx)
+
+	if candidate, ok := pool.next(section.Title); ok {
+		return candidate, true
+	}
+
+	if planned[section.Title] {
+		return Article{}, false
+	}
+
+	planned[section.Title] = true
+
+	var covered []string
+
+	for _, article := range articles {
+		if article.Section.Title == section.Title {
+			covered = append(covered, article.Headline)
+		}
+	}
+
+	for _, article := range pool.replacedArticles() {
+		if article.Section.Title == section.Title {
+			covered = append(covered, article.Headline)
+		}
+	}
+
+	more, err := planSection(ctx, section, covered)
+	if err != nil {
+		slog.Warn("fill_plan_failed",
+			slog.String("section", section.Title),
+			slog.String("error", err.Error()),
+		)
+
+		return Article{}, false
+	}
+
+	pool.add(section.Title, more)
+
+	return pool.next(section.Title)
+}
+
+// writeArticles runs stories through every article stage in a pipeline of
+// their own. Stages are skipped for articles that were dropped.
+func writeArticles(ctx context.Context, articles []Article) ([]Article, error) {
+	pipe, ctx := pipeline.WithPipeline(ctx)
+
+	source := make(chan Article, len(articles))
+	for _, article := range articles {
+		source <- article
+	}
+	close(source)
+
+	stages := articleStages()
+	for index, stage := range stages {
+		stages[index] = activeOnly(stage)
+	}
+
+	written := make(chan []Article, 1)
+	pipeline.Aggregate(pipe, transformArticles(pipe, stages, source, len(articles)), written)
+
+	if err := pipe.Wait(); err != nil {
+		return nil, err
+	}
+
+	return <-written, nil
+}
+
+// activeOnly wraps an article stage so it passes dropped articles through
+// untouched.
+func activeOnly(stage articleStage) articleStage {
+	return func(ctx context.Context, article Article) (*Article, error) {
+		if !article.Status.Active() {
+			return &article, nil
+		}
+
+		return stage(ctx, article)
+	}
+}
+
+// activeArticles returns the articles that have not been dropped.
+func activeArticles(articles []Article) []Article {
+	var active []Article
+
+	for _, article := range articles {
+		if article.Status.Active() {
+			active = append(active, article)
+		}
+	}
+
+	return active
+}
+
+// editionLength is the length of the document the active articles render to.
+func editionLength(ctx context.Context, articles []Article) int {
+	doc := models.Document{}
+
+	for _, article := range activeArticles(articles) {
+		addArticle(ctx, &doc, article)
+	}
+
+	return doc.Length()
+}
@@ -0,0 +1,180 @@
+package newspaper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fillAssistant plans the given headlines for any section, researches every
+// story and writes a short article from the research. It records the
+// planning requests it gets.
+func fillAssistant(plannedHeadlines ...string) (*stubAssistant, *[]string) {
+	var plans []string
+
+	assistant := &stubAssistant{
+		answer: func(persona, request string) (string, error) {
+			if persona == SectionPlanSystemPrompt {
+				plans = append(plans, request)
+				return "Story ideas for the section.", nil
+			}
+
+			return "The council passed the budget on Monday.", nil
+		},
+		structured: func(persona, request string) (string, error) {
+			switch {
+			case persona == SectionPlanSystemPrompt:
+				var articles []string
+
+				for _, headline := range plannedHeadlines {
+					articles = append(articles, fmt.Sprintf(`{"headline": %q, "summary": "A story.", "importance": 5, "format": "news"}`, headline))
+				}
+
+				return "[" + strings.Join(articles, ",") + "]", nil
+			case strings.Contains(request, "Extract the list of facts"):
+				return `[]`, nil
+			case persona == HeadlineSystemPrompt || persona == FactCheckSystemPrompt:
+				return "", assert.AnError
+			}
+
+			return `{"dek": "", "dateline": "", "byline": "", "key_points": [], "paragraphs": ["The council passed the budget on Monday."]}`, nil
+		},
+	}
+
+	return assistant, &plans
+}
+
+// fillContext is the context of an edition of the sections with a reserve of
+// stories for each section.
+func fillContext(assistant *stubAssistant, options NewspaperOptions, sections []Section, reserve map[string][]string) (context.Context, *candidatePool) {
+	pool := newCandidatePool()
+
+	for _, section := range sections {
+		var articles []Article
+
+		for _, headline := range reserve[section.Title] {
+			article := Article{Headline: headline, Section: section, Format: FormatNews, Importance: 5}
+			article.SetStatus(StagePlan, StatusPlanned, "")
+			articles = append(articles, article)
+		}
+
+		pool.add(section.Title, articles)
+	}
+
+	ctx := withOptions(context.Background(), options)
+	ctx = withSections(ctx, sections)
+	ctx = withCandidates(ctx, pool)
+
+	return withAssistant(ctx, assistant), pool
+}
+
+func headlines(articles []Article) []string {
+	var headlines []string
+
+	for _, article := range articles {
+		headlines = append(headlines, article.Headline)
+	}
+
+	return headlines
+}
+
+func TestFillCandidatesUsesReserveFirst(t *testing.T) {
+	local, world := Section{Title: "Local"}, Section{Title: "World"}
+
+	assistant, plans := fillAssistant("Planned story")
+	ctx, pool := fillContext(assistant, NewspaperOptions{MaxLength: 20000}, []Section{local, world}, map[string][]string{
+		local.Title: {"Library reopens", "Park gets new playground"},
+		world.Title: {"Summit ends"},
+	})
+
+	candidates := fillCandidates(ctx, nil, 3*(structureLength+minArticleLength), map[string]bool{})
+
+	// Sections take turns, in edition order, until enough stories are taken.
+	assert.Equal(t, []string{"Library reopens", "Summit ends", "Park gets new playground"}, headlines(candidates))
+	assert.Empty(t, *plans)
+	assert.Empty(t, pool.reservedArticles([]Section{local, world}))
+
+	for _, candidate := range candidates {
+		assert.GreaterOrEqual(t, candidate.TargetLength, minArticleLength)
+	}
+}
+
+func TestFillCandidatesPlansMoreOncePerSection(t *testing.T) {
+	local := Section{Title: "Local"}
+
+	assistant, plans := fillAssistant("Bridge closes", "Mayor resigns")
+	ctx, _ := fillContext(assistant, NewspaperOptions{MaxLength: 20000}, []Section{local}, map[string][]string{
+		local.Title: {"Library reopens"},
+	})
+
+	published := []Article{{Headline: "Council passes budget", Section: local, Body: "The council passed the budget."}}
+	planned := map[string]bool{}
+
+	candidates := fillCandidates(ctx, published, 10*(structureLength+minArticleLength), planned)
+
+	assert.Equal(t, []string{"Library reopens", "Bridge closes", "Mayor resigns"}, headlines(candidates))
+	require.Len(t, *plans, 1)
+	assert.Contains(t, (*plans)[0], "Council passes budget")
+	assert.True(t, planned[local.Title])
+
+	// The section already planned more stories, so it is not planned again.
+	candidates = fillCandidates(ctx, published, 10*(structureLength+minArticleLength), planned)
+
+	assert.Empty(t, candidates)
+	assert.Len(t, *plans, 1)
+}
+
+func TestFillCandidatesLimitsArticles(t *testing.T) {
+	local := Section{Title: "Local"}
+
+	assistant, _ := fillAssistant()
+	ctx, pool := fillContext(assistant, NewspaperOptions{MaxLength: 100000}, []Section{local}, map[string][]string{
+		local.Title: {"One", "Two", "Three", "Four", "Five", "Six"},
+	})
+
+	candidates := fillCandidates(ctx, nil, 100000, map[string]bool{})
+
+	assert.Len(t, candidates, maxFillArticles)
+	assert.Len(t, pool.reservedArticles([]Section{local}), 6-maxFillArticles)
+}
+
+func TestFillNewspaper(t *testing.T) {
+	local := Section{Title: "Local"}
+	reserve := []string{"One", "Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten"}
+
+	published := Article{Headline: "Council passes budget", Section: local, Body: "The council passed the budget."}
+	published.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+	tests := []struct {
+		name    string
+		options NewspaperOptions
+		written int
+	}{
+		{"disabled", NewspaperOptions{MaxLength: 100000}, 0},
+		{"already filled", NewspaperOptions{MaxLength: 100, MinFill: 0.5}, 0},
+		{"one round", NewspaperOptions{MaxLength: 160, MinFill: 0.5}, 1},
+		{"round limit", NewspaperOptions{MaxLength: 100000, MinFill: 1}, maxFillRounds * maxFillArticles},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assistant, plans := fillAssistant("Planned story")
+			ctx, _ := fillContext(assistant, test.options, []Section{local}, map[string][]string{local.Title: reserve})
+
+			filled, err := FillNewspaper(ctx, []Article{published})
+			require.NoError(t, err)
+
+			require.Len(t, *filled, 1+test.written)
+			assert.Equal(t, reserve[:test.written], append([]string{}, headlines((*filled)[1:])...))
+			assert.Empty(t, *plans)
+
+			for _, article := range (*filled)[1:] {
+				assert.Equal(t, StatusSynthesized, article.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package newspaper
+
+const (
+	ExplainerSystemPrompt = `
+		You are an expert newspaper explainer writer. Your task is to take
+		researched information about a complex recent event and write an
+		explainer that helps a general reader understand what happened, why it
+		matters, and who is affected. Each paragraph has 3 to 5 sentences and
+		answers one question a reader would have. Do not include any headings
+		or Markdown, HTML, LaTeX, or escape characters. The explainer should
+		be written in plain, neutral English, defining any technical terms.
+		` + synthesizeDateRangeRule
+
+	AnalysisSystemPrompt = `
+		You are an expert newspaper news analyst. Your task is to take
+		researched information about a recent development and write an
+		analysis of what it means and what may come next. Each paragraph has 3
+		to 5 sentences. Attribute every assessment and forecast to the people
+		or sources in the research; never offer your own opinion. Do not
+		include any headings or Markdown, HTML, LaTeX, or escape characters.
+		The analysis should be written in clear, neutral, newspaper-style
+		English.
+		` + synthesizeDateRangeRule
+
+	QASystemPrompt = `
+		You are an expert newspaper journalist. Your task is to take
+		researched information about a recent event and write a question and
+		answer article that addresses the questions readers are asking about
+		it. Each paragraph is one question followed by its answer in 2 to 4
+		sentences. Do not include any headings or Markdown, HTML, LaTeX, or
+		escape characters. The answers should be written in clear, neutral,
+		newspaper-style English.
+		` + synthesizeDateRangeRule
+
+	TimelineSystemPrompt = `
+		You are an expert newspaper journalist. Your task is to take
+		researched information about a recent story and write a timeline of
+		its developments in the order they happened. Each paragraph starts
+		with the date of a development followed by a colon and 1 to 3
+		sentences describing it. Do not include any headings or Markdown,
+		HTML, LaTeX, or escape characters. The timeline should be written in
+		clear, neutral, newspaper-style English.
+		` + synthesizeDateRangeRule
+
+	BriefSystemPrompt = `
+		You are an expert newspaper journalist. Your task is to take
+		researched information about a recent event and write a news brief:
+		one or two short paragraphs that state what happened, where, when and
+		why it matters. Do not include any headings or Markdown, HTML, LaTeX,
+		or escape characters. The brief should be written in clear, neutral,
+		newspaper-style English.
+		` + synthesizeDateRangeRule
+
+	synthesizeDateRangeRule = `
+		The user will provide a Date Range. Treat it as a hard constraint:
+		only include events, developments, and data points that occurred within
+		the Date Range (inclusive). Do not include background/history outside
+		the Date Range. If a claim is undated or the date is ambiguous, omit it.
+		`
+)
+
+// briefMaxLength bounds the length budgeted for a brief, in characters.
+const briefMaxLength = 1000
+
+type formatSpec struct {
+	// systemPrompt is the persona the article is written by.
+	systemPrompt string
+	// structure tells the journalist how to lay out the body.
+	structure string
+	// maxLength bounds the length budgeted for the body, in characters.
+	maxLength int
+	// trimmable is true when the last paragraphs of the body matter least,
+	// so the editor may drop them to fit the max length.
+	trimmable bool
+}
+
+var (
+	formatSpecs = map[ArticleFormat]formatSpec{
+		FormatNews: {
+			systemPrompt: SynthesizeSystemPrompt,
+			structure:    "A straight news story: lead with the most important facts, then supporting details, reactions and what happens next.",
+			maxLength:    maxArticleLength,
+			trimmable:    true,
+		},
+		FormatExplainer: {
+			systemPrompt: ExplainerSystemPrompt,
+			structure:    "An explainer: open with a paragraph summarizing the news, then give each following paragraph to one question a reader would ask, such as what happened, why it matters, who is affected and what comes next.",
+			maxLength:    maxArticleLength,
+			trimmable:    true,
+		},
+		FormatAnalysis: {
+			systemPrompt: AnalysisSystemPrompt,
+			structure:    "An analysis: open with the development, then examine its causes, its significance and the possible outcomes, attributing each assessment to a source in the research.",
+			maxLength:    maxArticleLength,
+			trimmable:    true,
+		},
+		FormatQA: {
+			systemPrompt: QASystemPrompt,
+			structure:    "Questions and answers: each paragraph is a question a reader would ask, ending with a question mark, followed by its answer.",
+			maxLength:    maxArticleLength,
+		},
+		FormatTimeline: {
+			systemPrompt: TimelineSystemPrompt,
+			structure:    "A timeline: open with a paragraph summarizing the story, then one paragraph per development in date order, each starting with its date and a colon, e.g. \"March 10: ...\".",
+			maxLength:    maxArticleLength,
+		},
+		FormatBrief: {
+			systemPrompt: BriefSystemPrompt,
+			structure:    "A brief: one or two short paragraphs with only the essential facts.",
+			maxLength:    briefMaxLength,
+		},
+	}
+
+	// articleFormats lists the formats the planner may choose from.
+	articleFormats = []string{
+		string(FormatNews),
+		string(FormatExplainer),
+		string(FormatAnalysis),
+		string(FormatQA),
+		string(FormatTimeline),
+		string(FormatBrief),
+	}
+)
+
+// spec returns the specification of the format, falling back to a straight
+// news story for unknown formats.
+func (f ArticleFormat) spec() formatSpec {
+	if spec, ok := formatSpecs[f]; ok {
+		return spec
+	}
+
+	return formatSpecs[FormatNews]
+}
+
+// systemPrompt is the persona articles of the format are written by.
+func (f ArticleFormat) systemPrompt() string {
+	return f.spec().systemPrompt
+}
+
+// structure describes how articles of the format lay out their body.
+func (f ArticleFormat) structure() string {
+	return f.spec().structure
+}
+
+// maxLength bounds the length budgeted for articles of the format.
+func (f ArticleFormat) maxLength() int {
+	return f.spec().maxLength
+}
+
+// trimmable reports whether articles of the format may lose their last
+// paragraphs to fit the max length. A timeline would lose its latest
+// developments, a Q&A whole answers and a brief most of itself.
+func (f ArticleFormat) trimmable() bool {
+	return f.spec().trimmable
+}
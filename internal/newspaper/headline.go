@@ -0,0 +1,338 @@
+package newspaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	HeadlineSystemPrompt = `
+		You are an expert newspaper copy editor. Your task is to write
+		headlines that tell the reader exactly what an article reports,
+		following the newspaper's style guide.
+		`
+
+	HeadlinePrompt = `
+		## Article
+		{{.Body}}
+
+		## Working Headline
+		{{.Headline}}
+
+		## Style Guide
+		- At most {{.MaxLength}} characters.
+		- Sentence case: capitalize only the first word and proper nouns.
+		- State the news in the active voice and present tense.
+		- Say only what the article says; never promise more than it delivers.
+		- No clickbait, questions, exclamation marks, or teasers that withhold the news.
+		{{if .Issues}}
+		## Problems
+		Previous headlines had the following problems. Do not repeat them.
+		{{range .Issues}}- {{.}}
+		{{end}}{{end}}
+		## Task
+		Write the final headline for the article. The working headline was
+		written before the article and may not match what it says.
+		`
+
+	HeadlineCheckPrompt = `
+		## Article
+		{{.Body}}
+
+		## Headline
+		{{.Headline}}
+
+		## Task
+		Extract every claim the headline makes about people, organizations,
+		events, numbers and outcomes. For each claim, check whether the article
+		body states it. A claim is not supported if the body does not mention
+		it, says something weaker, or contradicts it.
+		`
+)
+
+const (
+	checkHeadline = "headline"
+
+	// maxHeadlineLength is the most characters a headline may have.
+	maxHeadlineLength = 80
+
+	// maxHeadlineAttempts is how many headlines are written before keeping
+	// the working headline.
+	maxHeadlineAttempts = 2
+)
+
+// clickbaitPattern matches phrasing that teases the news instead of
+// reporting it.
+var clickbaitPattern = regexp.MustCompile(`(?i)\b(?:you won't believe|you need to know|here's (?:why|what|how)|this is (?:why|what|how)|what happens next|the reason why|will shock|shocking|stunning|jaw-dropping|mind-blowing|must-see|the secret (?:to|of))\b`)
+
+type headlineClaim struct {
+	Claim     string `json:"claim"`
+	Supported bool   `json:"supported"`
+}
+
+// RewriteHeadline replaces the working headline from planning with a final
+// headline written from the article body under the style guide, and checks
+// that the body supports every claim in it. When no headline passes after a
+// few attempts, the working headline is checked against the body in the same
+// way and kept, recording the problems of the rewrites along with any claims
+// of the working headline the body does not support.
+func RewriteHeadline(ctx context.Context, article Article) (*Article, error) {
+	if !article.Status.Active() {
+		return &article, nil
+	}
+
+	var issues []string
+
+	for attempt := 0; attempt < maxHeadlineAttempts; attempt++ {
+		headline, err := writeHeadline(ctx, article, issues)
+		if err != nil {
+			slog.Warn("headline_rewrite_failed",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.String("error", err.Error()),
+			)
+
+			return &article, nil
+		}
+
+		issues = headlineStyleIssues(*headline, article.Body)
+
+		if len(issues) == 0 {
+			issues, err = unsupportedHeadlineClaims(ctx, article, *headline)
+			if err != nil {
+				slog.Warn("headline_check_failed",
+					slog.String("section", article.Section.Title),
+					slog.String("headline", *headline),
+					slog.String("error", err.Error()),
+				)
+
+				return &article, nil
+			}
+		}
+
+		if len(issues) == 0 {
+			slog.Info("rewrote_headline",
+				slog.String("section", article.Section.Title),
+				slog.String("working_headline", article.Headline),
+				slog.String("headline", *headline),
+			)
+
+			article.Headline = *headline
+			article.AddCheck(checkHeadline, true, nil)
+
+			return &article, nil
+		}
+	}
+
+	unsupported, err := unsupportedHeadlineClaims(ctx, article, article.Headline)
+	if err != nil {
+		slog.Warn("headline_check_failed",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	issues = append(issues, unsupported...)
+
+	slog.Warn("headline_kept",
+		slog.String("section", article.Section.Title),
+		slog.String("headline", article.Headline),
+		slog.Bool("supported", err == nil && len(unsupported) == 0),
+		slog.Any("issues", issues),
+	)
+
+	article.AddCheck(checkHeadline, false, issues)
+
+	return &article, nil
+}
+
+// writeHeadline asks the copy editor for a headline for the article body,
+// avoiding the problems of previous attempts.
+func writeHeadline(ctx context.Context, article Article, issues []string) (*string, error) {
+	prompt, err := BuildPrompt(HeadlinePrompt, PromptArgs{
+		"Body":      article.Body,
+		"Headline":  article.Headline,
+		"MaxLength": maxHeadlineLength,
+		"Issues":    issues,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("headline prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"headline": map[string]any{
+				"type":        "string",
+				"description": "the final headline",
+			},
+		},
+		"required": []string{"headline"},
+	}
+
+	responseJson, err := structuredAsk(ctx, HeadlineSystemPrompt, *prompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("headline structured ask: %w", err)
+	}
+
+	var result struct {
+		Headline string
+	}
+
+	if err := json.Unmarshal(responseJson, &result); err != nil {
+		return nil, fmt.Errorf("headline unmarshal json: %w", err)
+	}
+
+	headline := normalizeText(result.Headline)
+	return &headline, nil
+}
+
+// unsupportedHeadlineClaims returns every claim in the headline that the
+// article body does not support.
+func unsupportedHeadlineClaims(ctx context.Context, article Article, headline string) ([]string, error) {
+	prompt, err := BuildPrompt(HeadlineCheckPrompt, PromptArgs{
+		"Body":     article.Body,
+		"Headline": headline,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("headline check prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"claims": map[string]any{
+				"type":        "array",
+				"description": "claims made by the headline",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"claim": map[string]any{
+							"type":        "string",
+							"description": "the claim",
+						},
+						"supported": map[string]any{
+							"type":        "boolean",
+							"description": "true if the article body supports the claim",
+						},
+					},
+					"required": []string{"claim", "supported"},
+				},
+			},
+		},
+		"required": []string{"claims"},
+	}
+
+	responseJson, err := structuredAsk(ctx, FactCheckSystemPrompt, *prompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("headline check structured ask: %w", err)
+	}
+
+	var result struct {
+		Claims []headlineClaim
+	}
+
+	if err := json.Unmarshal(responseJson, &result); err != nil {
+		return nil, fmt.Errorf("headline check unmarshal json: %w", err)
+	}
+
+	var issues []string
+
+	for _, claim := range result.Claims {
+		if !claim.Supported {
+			issues = append(issues, fmt.Sprintf("headline claim not supported by the article: %s", claim.Claim))
+		}
+	}
+
+	return issues, nil
+}
+
+// headlineStyleIssues returns the ways a headline breaks the style guide that
+// can be checked without the assistant. The article body tells proper nouns
+// apart from words in title case.
+func headlineStyleIssues(headline string, body string) []string {
+	if headline == "" {
+		return []string{"headline is empty"}
+	}
+
+	var issues []string
+
+	if length := len([]rune(headline)); length > maxHeadlineLength {
+		issues = append(issues, fmt.Sprintf("headline is %d characters, longer than %d", length, maxHeadlineLength))
+	}
+
+	if match := clickbaitPattern.FindString(headline); match != "" {
+		issues = append(issues, fmt.Sprintf("headline uses clickbait phrasing %q", match))
+	}
+
+	if strings.HasSuffix(headline, "?") || strings.HasSuffix(headline, "!") {
+		issues = append(issues, "headline ends with a question or exclamation mark")
+	}
+
+	if first := []rune(headline)[0]; unicode.IsLetter(first) && !unicode.IsUpper(first) {
+		issues = append(issues, "headline does not start with a capital letter")
+	}
+
+	if titleCase(headline, properNouns(body)) {
+		issues = append(issues, "headline is in title case instead of sentence case")
+	}
+
+	return issues
+}
+
+// titleCase reports whether most of the longer words after the first are
+// capitalized, which sentence case only allows for proper nouns. Words in
+// properNouns are proper nouns and are not counted.
+func titleCase(headline string, properNouns map[string]bool) bool {
+	words := strings.Fields(headline)
+	if len(words) < 2 {
+		return false
+	}
+
+	longWords, capitalized := 0, 0
+
+	for _, word := range words[1:] {
+		word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) })
+
+		runes := []rune(word)
+		if len(runes) < 4 || properNouns[word] {
+			continue
+		}
+
+		longWords++
+
+		if unicode.IsUpper(runes[0]) {
+			capitalized++
+		}
+	}
+
+	return longWords >= 3 && capitalized*2 > longWords
+}
+
+// properNouns returns the words the body capitalizes other than at the start
+// of a sentence.
+func properNouns(body string) map[string]bool {
+	nouns := map[string]bool{}
+
+	for _, paragraph := range strings.Split(body, "\n\n") {
+		for _, sentence := range splitSentences(strings.TrimSpace(paragraph)) {
+			words := strings.Fields(sentence)
+
+			for index := 1; index < len(words); index++ {
+				word := strings.TrimFunc(words[index], func(r rune) bool { return !unicode.IsLetter(r) })
+
+				if runes := []rune(word); len(runes) > 0 && unicode.IsUpper(runes[0]) {
+					nouns[word] = true
+				}
+			}
+		}
+	}
+
+	return nouns
+}
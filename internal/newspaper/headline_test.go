@@ -0,0 +1,32 @@
+package newspaper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeadlineStyleIssues(t *testing.T) {
+	body := "President Joe Biden met French President Emmanuel Macron in Paris on Monday.\n\n" +
+		"The Secret Service said it would review security at the rally."
+
+	assert.Empty(t, headlineStyleIssues("Port of Rotterdam reopens after two-day strike", ""))
+	assert.Empty(t, headlineStyleIssues("EU and UK agree fishing quotas", ""))
+	assert.Empty(t, headlineStyleIssues("Biden meets Macron in Paris", body))
+	assert.Empty(t, headlineStyleIssues("Secret Service reviews rally security", body))
+
+	tests := map[string]string{
+		"":                                        "headline is empty",
+		"Port Of Rotterdam Reopens After Strike":  "headline is in title case instead of sentence case",
+		"port reopens after strike":               "headline does not start with a capital letter",
+		"Will the port reopen after the strike?":  "headline ends with a question or exclamation mark",
+		"Here's why the port reopened so quickly": `headline uses clickbait phrasing "Here's why"`,
+		"The secret to the port's fast reopening": `headline uses clickbait phrasing "The secret to"`,
+		strings.Repeat("Port reopens ", 10):       "headline is 129 characters, longer than 80",
+	}
+
+	for headline, issue := range tests {
+		assert.Contains(t, headlineStyleIssues(strings.TrimSpace(headline), body), issue, headline)
+	}
+}
@@ -6,15 +6,37 @@ import (
 	"time"
 )
 
-func filterValidArticles(ctx context.Context, article Article) (bool, error) {
-	return article.Valid, nil
+// routeArticle sends active articles to the first output of a split and
+// dropped articles to the second.
+func routeArticle(ctx context.Context, article Article) int {
+	if article.Status.Active() {
+		return 0
+	}
+
+	return 1
+}
+
+// dateRange returns the inclusive start and end dates (UTC) of the newspaper run.
+func dateRange(ctx context.Context) (time.Time, time.Time) {
+	endTime := time.Now().UTC()
+	startTime := endTime.AddDate(0, 0, -optionsFrom(ctx).DaysBack)
+
+	return startTime, endTime
 }
 
 // dateRangeString returns the inclusive date range (UTC) used for the newspaper run.
 // It is intentionally formatted in ISO-8601 (YYYY-MM-DD) to avoid ambiguity in prompts.
 func dateRangeString(ctx context.Context) string {
-	endTime := time.Now().UTC()
-	startTime := endTime.AddDate(0, 0, -optionsFrom(ctx).DaysBack)
+	startTime, endTime := dateRange(ctx)
 
 	return fmt.Sprintf("%s to %s (inclusive, UTC)", startTime.Format("2006-01-02"), endTime.Format("2006-01-02"))
 }
+
+// inDateRange reports whether the calendar date of t falls within the
+// inclusive date range of the newspaper run.
+func inDateRange(ctx context.Context, t time.Time) bool {
+	startTime, endTime := dateRange(ctx)
+	date := t.UTC().Format("2006-01-02")
+
+	return date >= startTime.Format("2006-01-02") && date <= endTime.Format("2006-01-02")
+}
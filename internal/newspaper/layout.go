@@ -0,0 +1,243 @@
+package newspaper
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/schraf/assistant/pkg/models"
+)
+
+const (
+	// frontPageTitle is the title of the front page section of the document.
+	frontPageTitle = "Front Page"
+
+	// leadStoryHeading introduces the lead story on the front page.
+	leadStoryHeading = "Top story:"
+
+	// secondaryStoriesHeading introduces the secondary stories on the front page.
+	secondaryStoriesHeading = "Also in this edition:"
+
+	// contentsHeading introduces the table of contents on the front page.
+	contentsHeading = "Contents:"
+
+	// maxSecondaryStories is the most stories teased on the front page after
+	// the lead story.
+	maxSecondaryStories = 3
+
+	// minFrontPageArticles is the fewest articles an edition needs for a
+	// front page; a single story is its own front page.
+	minFrontPageArticles = 2
+
+	// teaserSeparator separates a headline from its teaser on the front page.
+	teaserSeparator = " — "
+
+	// maxTeaserLength bounds the length of a teaser, in characters, so it
+	// fits on one line.
+	maxTeaserLength = 160
+)
+
+// LayoutNewspaper lays out the edited articles: sections keep their edition
+// order and the articles of each section are ordered by importance. The most
+// important story of the edition leads, the next few are secondary stories,
+// and a front page with their headlines and teasers and a table of contents
+// of every section opens the document. The editor reserves room for the
+// front page, so the laid out edition still fits the max length.
+func LayoutNewspaper(ctx context.Context, edition Edition) (*Edition, error) {
+	articles := layoutArticles(edition.Articles)
+	stories := frontPageStories(articles)
+
+	for index := range articles {
+		articles[index].Placement = PlacementInside
+	}
+
+	for place, index := range stories {
+		if place == 0 {
+			articles[index].Placement = PlacementLead
+		} else {
+			articles[index].Placement = PlacementSecondary
+		}
+	}
+
+	doc := models.Document{Title: edition.Document.Title, Author: edition.Document.Author}
+
+	if len(articles) >= minFrontPageArticles {
+		doc.Sections = append(doc.Sections, frontPage(articles, stories))
+	}
+
+	for _, article := range articles {
+		addArticle(ctx, &doc, article)
+	}
+
+	if len(stories) > 0 {
+		slog.Info("layout_finished",
+			slog.String("lead", articles[stories[0]].Headline),
+			slog.Int("secondary", len(stories)-1),
+			slog.Int("length", doc.Length()),
+		)
+	}
+
+	if maxLength := optionsFrom(ctx).MaxLength; maxLength > 0 && doc.Length() > maxLength {
+		slog.Warn("layout_exceeds_max_length",
+			slog.Int("length", doc.Length()),
+			slog.Int("max_length", maxLength),
+		)
+	}
+
+	return &Edition{
+		Document: doc,
+		Articles: articles,
+		Dropped:  edition.Dropped,
+	}, nil
+}
+
+// layoutArticles orders the articles by section, in edition order, and then
+// by importance within each section, keeping plan order for equal importance.
+func layoutArticles(articles []Article) []Article {
+	ordered := append([]Article(nil), articles...)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Section.order != ordered[j].Section.order {
+			return ordered[i].Section.order < ordered[j].Section.order
+		}
+
+		if ordered[i].Importance != ordered[j].Importance {
+			return ordered[i].Importance > ordered[j].Importance
+		}
+
+		return ordered[i].Rank < ordered[j].Rank
+	})
+
+	return ordered
+}
+
+// frontPageStories picks the lead story followed by the secondary stories:
+// the most important stories of the edition, with earlier sections and plan
+// order breaking ties. The editor scores multi-section editions, so their
+// importance compares stories across sections. It returns the indexes of the
+// stories in the laid out articles.
+func frontPageStories(articles []Article) []int {
+	stories := make([]int, len(articles))
+
+	for index := range stories {
+		stories[index] = index
+	}
+
+	// Laid out articles are already in section and plan order, so a stable
+	// sort by importance breaks ties between sections by their order.
+	sort.SliceStable(stories, func(i, j int) bool {
+		return articles[stories[i]].Importance > articles[stories[j]].Importance
+	})
+
+	return stories[:min(len(stories), 1+maxSecondaryStories)]
+}
+
+// frontPage renders the front page: the lead story and the secondary stories
+// with a teaser each, followed by a table of contents with the headlines of
+// every section.
+func frontPage(articles []Article, stories []int) models.DocumentSection {
+	page := models.DocumentSection{Title: frontPageTitle}
+
+	if len(stories) > 0 {
+		page.Paragraphs = append(page.Paragraphs, leadStoryHeading, storyLine(articles[stories[0]]))
+	}
+
+	if len(stories) > 1 {
+		page.Paragraphs = append(page.Paragraphs, secondaryStoriesHeading)
+
+		for _, index := range stories[1:] {
+			page.Paragraphs = append(page.Paragraphs, "• "+storyLine(articles[index]))
+		}
+	}
+
+	page.Paragraphs = append(page.Paragraphs, contentsHeading)
+	page.Paragraphs = append(page.Paragraphs, contents(articles)...)
+
+	return page
+}
+
+// frontPageLength bounds the length of the front page of any edition made
+// of some of the articles, whichever of them lead: it counts the longest
+// story lines and the contents of every article.
+func frontPageLength(articles []Article) int {
+	if len(articles) < minFrontPageArticles {
+		return 0
+	}
+
+	lines := make([]int, len(articles))
+
+	for index, article := range articles {
+		lines[index] = len("• " + storyLine(article))
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(lines)))
+
+	length := len(frontPageTitle) + len(leadStoryHeading) + len(secondaryStoriesHeading) + len(contentsHeading)
+
+	for _, line := range lines[:min(len(lines), 1+maxSecondaryStories)] {
+		length += line
+	}
+
+	for _, paragraph := range contents(layoutArticles(articles)) {
+		length += len(paragraph)
+	}
+
+	return length
+}
+
+// contents lists the headlines of each section, one paragraph per section.
+func contents(articles []Article) []string {
+	var paragraphs []string
+	var headlines []string
+
+	for index, article := range articles {
+		headlines = append(headlines, article.Headline)
+
+		if index+1 == len(articles) || articles[index+1].Section.Title != article.Section.Title {
+			paragraphs = append(paragraphs, article.Section.Title+": "+strings.Join(headlines, "; "))
+			headlines = nil
+		}
+	}
+
+	return paragraphs
+}
+
+// storyLine is the headline of an article followed by its teaser.
+func storyLine(article Article) string {
+	if teaser := teaser(article); teaser != "" {
+		return article.Headline + teaserSeparator + teaser
+	}
+
+	return article.Headline
+}
+
+// teaser is a one-line summary of an article: its dek, or else its first key
+// point or the first sentence of its body, shortened at a word boundary when
+// it is too long for one line.
+func teaser(article Article) string {
+	text := strings.TrimSpace(article.Dek)
+
+	if text == "" && len(article.KeyPoints) > 0 {
+		text = strings.TrimSpace(article.KeyPoints[0])
+	}
+
+	if text == "" {
+		paragraph, _, _ := strings.Cut(strings.TrimSpace(article.Body), "\n\n")
+
+		if sentences := splitSentences(paragraph); len(sentences) > 0 {
+			text = strings.TrimSpace(sentences[0])
+		}
+	}
+
+	if len(text) <= maxTeaserLength {
+		return text
+	}
+
+	cut := strings.LastIndex(text[:maxTeaserLength], " ")
+	if cut <= 0 {
+		return text
+	}
+
+	return strings.TrimRight(text[:cut], " ,;:") + "…"
+}
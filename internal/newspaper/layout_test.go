@@ -0,0 +1,77 @@
+package newspaper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayoutNewspaper(t *testing.T) {
+	ctx := withOptions(context.Background(), NewspaperOptions{})
+	us := Section{Title: "US", order: 0}
+	world := Section{Title: "World", order: 1}
+
+	edition := Edition{
+		Articles: []Article{
+			{Section: world, Headline: "World minor", Importance: 3, Rank: 0, Body: "Minor news."},
+			{Section: us, Headline: "US second", Importance: 5, Rank: 1, Dek: "The second story."},
+			{Section: world, Headline: "World lead", Importance: 9, Rank: 1, KeyPoints: []string{"The top point."}},
+			{Section: us, Headline: "US first", Importance: 7, Rank: 0, Body: "First sentence. Second sentence."},
+		},
+	}
+
+	laid, err := LayoutNewspaper(ctx, edition)
+	require.NoError(t, err)
+
+	var headlines []string
+	for _, article := range laid.Articles {
+		headlines = append(headlines, article.Headline)
+	}
+
+	assert.Equal(t, []string{"US first", "US second", "World lead", "World minor"}, headlines)
+	assert.Equal(t, PlacementLead, laid.Articles[2].Placement)
+	assert.Equal(t, PlacementSecondary, laid.Articles[0].Placement)
+	assert.Equal(t, PlacementSecondary, laid.Articles[3].Placement)
+
+	require.Len(t, laid.Document.Sections, 5)
+
+	page := laid.Document.Sections[0]
+	assert.Equal(t, frontPageTitle, page.Title)
+	assert.Equal(t, []string{
+		leadStoryHeading,
+		"World lead — The top point.",
+		secondaryStoriesHeading,
+		"• US first — First sentence.",
+		"• US second — The second story.",
+		"• World minor — Minor news.",
+		contentsHeading,
+		"US: US first; US second",
+		"World: World lead; World minor",
+	}, page.Paragraphs)
+
+	assert.LessOrEqual(t, sectionLength(page), frontPageLength(edition.Articles))
+}
+
+func TestLayoutNewspaperSingleArticle(t *testing.T) {
+	ctx := withOptions(context.Background(), NewspaperOptions{})
+
+	laid, err := LayoutNewspaper(ctx, Edition{Articles: []Article{{Headline: "Only", Body: "Only story."}}})
+	require.NoError(t, err)
+
+	require.Len(t, laid.Document.Sections, 1)
+	assert.Equal(t, "Only", laid.Document.Sections[0].Title)
+	assert.Equal(t, PlacementLead, laid.Articles[0].Placement)
+	assert.Zero(t, frontPageLength(laid.Articles))
+}
+
+func TestTeaser(t *testing.T) {
+	long := strings.Repeat("word ", 50)
+	short := teaser(Article{Dek: long})
+
+	assert.LessOrEqual(t, len(short), maxTeaserLength+len("…"))
+	assert.True(t, strings.HasSuffix(short, "word…"))
+	assert.Empty(t, teaser(Article{}))
+}
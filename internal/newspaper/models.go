@@ -1,21 +1,289 @@
 package newspaper
 
+import (
+	"strings"
+	"time"
+
+	"github.com/schraf/assistant/pkg/models"
+)
+
 type NewspaperOptions struct {
-	DaysBack  int
-	MaxLength int
-	Location  string
+	DaysBack       int
+	MaxLength      int
+	Location       string
+	IncludeSources bool
+	ResearchDepth  int
+	SourcePolicy   SourcePolicy
+
+	// ResearchJudgment asks the assistant to judge whether research is
+	// sufficient to write each article, in addition to the refusal heuristic.
+	ResearchJudgment bool
+
+	// ArticlesPerSection limits how many planned stories of each section are
+	// researched. The remaining stories are held in reserve. Zero uses every
+	// planned story.
+	ArticlesPerSection int
+	// RetryBlockedResearch retries research that was content blocked with a
+	// reframed, strictly factual prompt.
+	RetryBlockedResearch bool
+	// SubstituteBlockedStories replaces stories whose research is still
+	// content blocked with the next reserve story of the section.
+	SubstituteBlockedStories bool
+
+	// FactCheck controls what happens to synthesized articles with claims
+	// the research notes do not support.
+	FactCheck FactCheckMode
+	// DateStrictness controls what happens to sentences in research and
+	// articles that mention dates before the Date Range.
+	DateStrictness DateStrictness
+	// NumberCheck verifies that every figure in an article appears in its
+	// research notes, sending articles back to synthesis when they do not.
+	NumberCheck bool
+	// CopyThreshold is the fraction of an article's word sequences that may
+	// also appear in its research before the article is sent back for a
+	// rewrite. Zero disables the check.
+	CopyThreshold float64
+	// ReviewRounds is how many times an editor critiques each article and
+	// the journalist revises it. Zero disables the review.
+	ReviewRounds int
+	// CondenseTrimmed asks the journalist to condense articles the editor
+	// trims to fit the max length, instead of only dropping their last
+	// paragraphs.
+	CondenseTrimmed bool
+	// MinFill is the fraction of the max length the finished articles
+	// should fill. When they fall short, additional stories are written
+	// from the reserve or from planning more. Zero disables filling.
+	MinFill float64
+
+	// ResearchCacheDir is the directory research is cached in. Caching is
+	// disabled when it is empty.
+	ResearchCacheDir string
+	// ResearchCacheTTL is how long cached research stays fresh. Zero means
+	// cached research never expires.
+	ResearchCacheTTL time.Duration
 }
 
 type Section struct {
-	Title       string
-	Description string
+	Title        string
+	Description  string
+	SourcePolicy SourcePolicy
+
+	// MinArticles and MaxArticles bound how many articles of the section
+	// the editor keeps. Zero means no bound.
+	MinArticles int
+	MaxArticles int
+	// MinShare and MaxShare bound the fraction of the max length the
+	// articles of the section may take up when the editor has to cut. Zero
+	// means no bound.
+	MinShare float64
+	MaxShare float64
+
+	// order is the position of the section in the edition.
+	order int
+}
+
+const (
+	minImportance = 1
+	maxImportance = 10
+)
+
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+// Fact is a single dated claim gathered during research along with the
+// source it was taken from.
+type Fact struct {
+	Statement  string     `json:"statement"`
+	Date       string     `json:"date"`
+	SourceName string     `json:"source_name"`
+	SourceURL  string     `json:"source_url"`
+	Confidence Confidence `json:"confidence"`
+}
+
+// Time parses the fact date. It returns false when the fact is undated or
+// the date is not in ISO-8601 (YYYY-MM-DD) format.
+func (f Fact) Time() (time.Time, bool) {
+	date, err := time.Parse("2006-01-02", f.Date)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+// Source is an outlet cited by one or more facts of an article.
+type Source struct {
+	Name string
+	URL  string
+}
+
+// ArticleStatus is where an article is in the newspaper pipeline, or why it
+// left the pipeline.
+type ArticleStatus string
+
+const (
+	StatusPlanned      ArticleStatus = "planned"
+	StatusResearched   ArticleStatus = "researched"
+	StatusBlocked      ArticleStatus = "blocked"
+	StatusInsufficient ArticleStatus = "insufficient"
+	StatusFailed       ArticleStatus = "failed"
+	StatusSynthesized  ArticleStatus = "synthesized"
+	StatusRejected     ArticleStatus = "rejected"
+	StatusCutByEditor  ArticleStatus = "cut-by-editor"
+	StatusReserved     ArticleStatus = "reserved"
+)
+
+// Active reports whether an article with this status is still on its way
+// to publication.
+func (s ArticleStatus) Active() bool {
+	switch s {
+	case StatusPlanned, StatusResearched, StatusSynthesized:
+		return true
+	default:
+		return false
+	}
 }
 
+// FactCheckMode is what the fact check stage does with articles that make
+// unsupported claims.
+type FactCheckMode string
+
+const (
+	FactCheckOff    FactCheckMode = "off"
+	FactCheckRevise FactCheckMode = "revise"
+	FactCheckDrop   FactCheckMode = "drop"
+)
+
+// ArticleFormat is the form a story is written in.
+type ArticleFormat string
+
+const (
+	FormatNews      ArticleFormat = "news"
+	FormatExplainer ArticleFormat = "explainer"
+	FormatAnalysis  ArticleFormat = "analysis"
+	FormatQA        ArticleFormat = "q-and-a"
+	FormatTimeline  ArticleFormat = "timeline"
+	FormatBrief     ArticleFormat = "brief"
+)
+
+// Stage names the pipeline stage that last set the status of an article.
+type Stage string
+
+const (
+	StagePlan        Stage = "plan"
+	StageResearch    Stage = "research"
+	StageSufficiency Stage = "sufficiency"
+	StageSynthesize  Stage = "synthesize"
+	StageFactCheck   Stage = "fact-check"
+	StageDateCheck   Stage = "date-check"
+	StageNumberCheck Stage = "number-check"
+	StageCopyCheck   Stage = "copy-check"
+	StageQuarantine  Stage = "quarantine"
+	StageNormalize   Stage = "normalize"
+	StageEdit        Stage = "edit"
+)
+
 type Article struct {
-	Valid    bool
-	Section  Section
-	Headline string
-	Summary  string
-	Research string
-	Body     string
+	Status       ArticleStatus
+	StatusReason string
+	StatusStage  Stage
+	Section      Section
+	Headline     string
+	Summary      string
+	// Rank is the position of the article in the plan of its section, most
+	// important first.
+	Rank int
+	// Format is the form the article is written in.
+	Format ArticleFormat
+	// Placement is where the layout put the article in the edition.
+	Placement Placement
+	// Importance is how important the story is to readers of its section,
+	// from 1 (minor) to 10 (major). When the editor scores the edition it
+	// becomes the importance to readers of the whole edition.
+	Importance int
+	// TargetLength is the length in characters the article body is budgeted.
+	// Zero means the article has no budget.
+	TargetLength int
+	Research     string
+	Facts        []Fact
+	Sources      []Source
+	// Dek is the subheadline of the article.
+	Dek string
+	// Dateline is the location and date the article was reported from.
+	Dateline string
+	// Byline labels the desk the article is credited to.
+	Byline string
+	// KeyPoints summarize the article in a few sentences.
+	KeyPoints []string
+	// Body holds the paragraphs of the article separated by blank lines.
+	Body string
+	// Corrections are problems with a previous draft of the article that
+	// synthesis must avoid.
+	Corrections []string
+	Checks      []CheckResult
+}
+
+// Placement is where an article is featured in the edition.
+type Placement string
+
+const (
+	// PlacementLead is the lead story, featured first on the front page.
+	PlacementLead Placement = "lead"
+	// PlacementSecondary is a story teased on the front page after the lead.
+	PlacementSecondary Placement = "secondary"
+	// PlacementInside is a story only listed in the table of contents.
+	PlacementInside Placement = "inside"
+)
+
+// CheckResult is the outcome of a quality check run on an article.
+type CheckResult struct {
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	// Revised is true when the article was revised to address the problems
+	// the check found.
+	Revised bool `json:"revised"`
+	// Issues are the problems that remain after any revision.
+	Issues []string `json:"issues,omitempty"`
+}
+
+// AddCheck records the outcome of a quality check.
+func (a *Article) AddCheck(check string, revised bool, issues []string) {
+	a.Checks = append(a.Checks, CheckResult{
+		Check:   check,
+		Passed:  len(issues) == 0,
+		Revised: revised,
+		Issues:  issues,
+	})
+}
+
+// SetStatus records the status of the article along with the stage that set
+// it and the reason.
+func (a *Article) SetStatus(stage Stage, status ArticleStatus, reason string) {
+	a.Status = status
+	a.StatusStage = stage
+	a.StatusReason = reason
+}
+
+// text is everything readers see of the article besides its headline: the
+// dek, the key points and the body, separated by blank lines.
+func (a Article) text() string {
+	parts := append([]string{a.Dek}, a.KeyPoints...)
+	return strings.Join(append(parts, a.Body), "\n\n")
+}
+
+// Edition is a finished newspaper along with every article that was planned
+// for it, published or not.
+type Edition struct {
+	Document models.Document
+	// Articles are the articles published in the document, in order.
+	Articles []Article
+	// Dropped are the articles that never made the paper, including reserve
+	// stories that were never needed. Their status and status reason record
+	// why.
+	Dropped []Article
 }
@@ -0,0 +1,211 @@
+package newspaper
+
+import (
+	"context"
+	"html"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const (
+	checkNormalize = "normalize"
+
+	// maxParagraphSentences is the most sentences a paragraph may have
+	// before it is split.
+	maxParagraphSentences = 6
+
+	// splitParagraphSentences is the number of sentences each part of a split
+	// paragraph aims for.
+	splitParagraphSentences = 4
+)
+
+var (
+	// escapeReplacer turns literal escape sequences the model wrote into the
+	// characters they stand for.
+	escapeReplacer = strings.NewReplacer(`\r\n`, "\n", `\n`, "\n", `\t`, " ", `\"`, `"`, `\'`, "'")
+
+	// quoteReplacer turns typographic quotes and spaces into plain ones.
+	quoteReplacer = strings.NewReplacer(
+		"“", `"`, "”", `"`, "„", `"`, "«", `"`, "»", `"`,
+		"‘", "'", "’", "'", "‚", "'",
+		"\u00a0", " ", "\u2009", " ", "\u202f", " ",
+	)
+
+	// blockTagPattern matches HTML tags that separate paragraphs.
+	blockTagPattern = regexp.MustCompile(`(?i)</?(?:p|div|h[1-6]|ul|ol|blockquote|section|article)\b[^>]*>`)
+
+	// lineTagPattern matches HTML tags that separate lines.
+	lineTagPattern = regexp.MustCompile(`(?i)<br\s*/?>|</?li\b[^>]*>`)
+
+	// htmlTagPattern matches any remaining HTML tag.
+	htmlTagPattern = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+
+	// headingPattern matches Markdown headings, bold lines standing in for a
+	// heading, and the underlines of setext headings.
+	headingPattern = regexp.MustCompile(`^(?:#{1,6}\s.*|#{1,6}|(?:\*\*|__)[^.!?]*(?:\*\*|__):?|=+|-{2,})$`)
+
+	// ruleLinePattern matches horizontal rules and code fences.
+	ruleLinePattern = regexp.MustCompile("^(?:(?:[-*_]\\s*){3,}|```.*|~~~.*)$")
+
+	// listMarkerPattern matches the marker of a list item or block quote.
+	listMarkerPattern = regexp.MustCompile(`^(?:[-*+•]|\d+[.)]|>)\s+`)
+
+	// linkPattern matches Markdown links and images.
+	linkPattern = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+	// emphasisPattern matches bold, strike-through and inline code spans.
+	emphasisPattern = regexp.MustCompile("\\*\\*(.+?)\\*\\*|__(.+?)__|~~(.+?)~~|`([^`]*)`")
+
+	// italicPattern matches italic spans.
+	italicPattern = regexp.MustCompile(`(^|[^\w*])[*_]([^*_\s](?:[^*_]*[^*_\s])?)[*_]([^\w*]|$)`)
+
+	// markdownEscapePattern matches backslash escapes of Markdown characters.
+	markdownEscapePattern = regexp.MustCompile("\\\\([\\\\`*_{}\\[\\]()#+\\-.!>|])")
+
+	// spacePattern matches runs of horizontal whitespace.
+	spacePattern = regexp.MustCompile(`[ \t\f\v]+`)
+)
+
+// NormalizeArticle cleans the Markdown, HTML and escape characters out of the
+// article, fixes its quotes and whitespace, and splits the body into real
+// paragraphs. Articles whose body is only headings or ends mid-sentence are
+// rejected.
+func NormalizeArticle(ctx context.Context, article Article) (*Article, error) {
+	if !article.Status.Active() {
+		return &article, nil
+	}
+
+	paragraphs, headings := normalizeBody(article.Body)
+
+	var issue string
+
+	switch {
+	case len(paragraphs) == 0 && headings > 0:
+		issue = "body contains only headings"
+	case len(paragraphs) == 0:
+		issue = "body is empty"
+	case truncated(paragraphs[len(paragraphs)-1]):
+		issue = "body ends mid-sentence"
+	}
+
+	if issue != "" {
+		slog.Warn("article_body_rejected",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.String("issue", issue),
+		)
+
+		article.AddCheck(checkNormalize, false, []string{issue})
+		article.SetStatus(StageNormalize, StatusRejected, issue)
+		return &article, nil
+	}
+
+	body := strings.Join(paragraphs, "\n\n")
+	revised := body != article.Body
+
+	article.Body = body
+	article.Headline = normalizeText(article.Headline)
+	article.Dek = normalizeText(article.Dek)
+	article.Dateline = normalizeText(article.Dateline)
+	article.Byline = normalizeText(article.Byline)
+
+	var keyPoints []string
+
+	for _, point := range article.KeyPoints {
+		if point = normalizeText(listMarkerPattern.ReplaceAllString(strings.TrimSpace(point), "")); point != "" {
+			keyPoints = append(keyPoints, point)
+		}
+	}
+
+	article.KeyPoints = keyPoints
+	article.AddCheck(checkNormalize, revised, nil)
+
+	return &article, nil
+}
+
+// normalizeBody cleans the markup out of an article body and returns its
+// paragraphs along with the number of headings that were removed.
+func normalizeBody(body string) ([]string, int) {
+	body = hiddenTextPattern.ReplaceAllString(escapeReplacer.Replace(body), "")
+	body = blockTagPattern.ReplaceAllString(body, "\n\n")
+	body = lineTagPattern.ReplaceAllString(body, "\n")
+	body = htmlTagPattern.ReplaceAllString(body, "")
+
+	var paragraphs []string
+	var lines []string
+	headings := 0
+
+	flush := func() {
+		if len(lines) > 0 {
+			paragraphs = append(paragraphs, splitParagraph(strings.Join(lines, " "))...)
+			lines = nil
+		}
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "":
+			flush()
+		case ruleLinePattern.MatchString(line):
+			flush()
+		case headingPattern.MatchString(line):
+			headings++
+			flush()
+		default:
+			if line = normalizeText(listMarkerPattern.ReplaceAllString(line, "")); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	flush()
+
+	return paragraphs, headings
+}
+
+// normalizeText removes inline markup from a single line of text and fixes
+// its quotes and whitespace.
+func normalizeText(text string) string {
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, ""))
+	text = linkPattern.ReplaceAllString(text, "$1")
+	text = emphasisPattern.ReplaceAllString(text, "$1$2$3$4")
+	text = italicPattern.ReplaceAllString(text, "$1$2$3")
+	text = markdownEscapePattern.ReplaceAllString(text, "$1")
+	text = quoteReplacer.Replace(text)
+
+	return strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+}
+
+// splitParagraph splits a paragraph with too many sentences into parts of
+// about the same number of sentences.
+func splitParagraph(paragraph string) []string {
+	sentences := splitSentences(paragraph)
+	if len(sentences) <= maxParagraphSentences {
+		return []string{paragraph}
+	}
+
+	parts := (len(sentences) + splitParagraphSentences - 1) / splitParagraphSentences
+	paragraphs := make([]string, 0, parts)
+
+	for part := 0; part < parts; part++ {
+		begin := part * len(sentences) / parts
+		end := (part + 1) * len(sentences) / parts
+		paragraphs = append(paragraphs, strings.Join(sentences[begin:end], " "))
+	}
+
+	return paragraphs
+}
+
+// truncated reports whether a paragraph stops before the end of its last
+// sentence.
+func truncated(paragraph string) bool {
+	paragraph = strings.TrimRight(paragraph, `"')]`)
+	if paragraph == "" {
+		return true
+	}
+
+	return !strings.ContainsAny(paragraph[len(paragraph)-1:], ".!?")
+}
@@ -0,0 +1,52 @@
+package newspaper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeArticle(t *testing.T) {
+	article := Article{
+		Status:   StatusSynthesized,
+		Headline: "**Port Reopens**",
+		KeyPoints: []string{
+			"- The port reopened on Monday.",
+		},
+		Body: "## Port Reopens\n\n" +
+			"The port **reopened** on Monday after a <em>two-day</em> strike.\\nOfficials said “300 ships” were delayed.\n\n" +
+			"- Union leaders welcomed the [deal](https://example.com/deal).\n" +
+			"- Shipping firms said delays would ease by Friday.\n\n" +
+			"---\n\n" +
+			"One. Two. Three. Four. Five. Six. Seven. Eight.",
+	}
+
+	result, err := NormalizeArticle(context.Background(), article)
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusSynthesized, result.Status)
+	assert.Equal(t, "Port Reopens", result.Headline)
+	assert.Equal(t, []string{"The port reopened on Monday."}, result.KeyPoints)
+	assert.Equal(t, "The port reopened on Monday after a two-day strike. Officials said \"300 ships\" were delayed.\n\n"+
+		"Union leaders welcomed the deal. Shipping firms said delays would ease by Friday.\n\n"+
+		"One. Two. Three. Four.\n\n"+
+		"Five. Six. Seven. Eight.", result.Body)
+}
+
+func TestNormalizeArticleRejects(t *testing.T) {
+	tests := map[string]string{
+		"body contains only headings": "# Port Reopens\n\n**Background**",
+		"body ends mid-sentence":      "The port reopened on Monday. Officials said the",
+		"body is empty":               "  \n\n ",
+	}
+
+	for issue, body := range tests {
+		result, err := NormalizeArticle(context.Background(), Article{Status: StatusSynthesized, Body: body})
+		require.NoError(t, err)
+
+		assert.Equal(t, StatusRejected, result.Status, issue)
+		assert.Equal(t, issue, result.StatusReason)
+	}
+}
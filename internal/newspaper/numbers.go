@@ -0,0 +1,284 @@
+package newspaper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxNumberCorrections is how many times an article with unsupported
+// numbers is sent back to synthesis before it is rejected.
+const maxNumberCorrections = 1
+
+const (
+	checkArticleNumbers        = "article-numbers"
+	checkArticleNumbersRecheck = "article-numbers-recheck"
+)
+
+const NumbersReviseTask = `
+		These figures in the article do not appear in the research notes.
+		Correct each one to the exact figure from the research notes, or
+		remove the sentence containing it when the research notes do not
+		cover it.
+		`
+
+// numberPattern matches a number with an optional currency symbol, scale
+// word and unit, e.g. "$2.5 million", "1,200", "15%" or "40 percent".
+var numberPattern = regexp.MustCompile(`(?i)([$€£])?\s?(\d{1,3}(?:,\d{3})+|\d+)(\.\d+)?(?:\s?(thousand|million|billion|trillion|bn|m)\b)?(?:\s?(%|percent\b|per cent\b))?`)
+
+var numberScales = map[string]float64{
+	"thousand": 1e3,
+	"million":  1e6,
+	"m":        1e6,
+	"billion":  1e9,
+	"bn":       1e9,
+	"trillion": 1e12,
+}
+
+// figure is a number mentioned in text along with the precision it was
+// written with.
+type figure struct {
+	text      string
+	value     float64
+	precision float64
+	unit      bool
+}
+
+// CheckArticleNumbers verifies that every figure in the article body, dek
+// and key points also appears in the research notes. Articles with
+// unsupported figures are sent back to synthesis with a correction request,
+// and rejected if the figures are still unsupported afterwards. Synthesis
+// writes the body from scratch, so the check runs straight after it, before
+// any stage revises the body; RecheckArticleNumbers checks the revisions.
+func CheckArticleNumbers(ctx context.Context, article Article) (*Article, error) {
+	if !article.Status.Active() || !optionsFrom(ctx).NumberCheck {
+		return &article, nil
+	}
+
+	unsupported := unsupportedFigures(article.text(), article.Research)
+	revised := false
+
+	for attempt := 1; len(unsupported) > 0 && attempt <= maxNumberCorrections; attempt++ {
+		slog.Warn("article_numbers_unsupported",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Int("attempt", attempt),
+			slog.Any("figures", unsupported),
+		)
+
+		corrected := article
+		corrected.Corrections = nil
+
+		for _, number := range unsupported {
+			corrected.Corrections = append(corrected.Corrections,
+				fmt.Sprintf("The figure %q does not appear in the research notes. Use the exact figure from the research notes or leave it out.", number))
+		}
+
+		resynthesized, err := SynthesizeArticle(ctx, corrected)
+		if err != nil {
+			return nil, err
+		}
+
+		if !resynthesized.Status.Active() {
+			break
+		}
+
+		article = *resynthesized
+		revised = true
+		unsupported = unsupportedFigures(article.text(), article.Research)
+	}
+
+	article.AddCheck(checkArticleNumbers, revised, unsupported)
+
+	if len(unsupported) > 0 {
+		article.SetStatus(StageNumberCheck, StatusRejected, fmt.Sprintf("%d figures not found in research", len(unsupported)))
+	}
+
+	return &article, nil
+}
+
+// RecheckArticleNumbers checks the figures of an article again once review,
+// copy and fact check revisions have rewritten it, since a revision may add
+// figures of its own. Unsupported figures are sent back to the journalist
+// for one revision, and the article is rejected if they remain.
+func RecheckArticleNumbers(ctx context.Context, article Article) (*Article, error) {
+	if !article.Status.Active() || !optionsFrom(ctx).NumberCheck || !revisedSince(article, checkArticleNumbers) {
+		return &article, nil
+	}
+
+	unsupported := unsupportedFigures(article.text(), article.Research)
+	revised := false
+
+	if len(unsupported) > 0 {
+		slog.Warn("article_numbers_unsupported_after_revision",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Any("figures", unsupported),
+		)
+
+		draft, err := reviseArticle(ctx, article, NumbersReviseTask, unsupported)
+		if err != nil {
+			slog.Warn("article_numbers_revise_failed",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			article = *draft
+			revised = true
+			unsupported = unsupportedFigures(article.text(), article.Research)
+		}
+	}
+
+	article.AddCheck(checkArticleNumbersRecheck, revised, unsupported)
+
+	if len(unsupported) > 0 {
+		article.SetStatus(StageNumberCheck, StatusRejected, fmt.Sprintf("%d figures not found in research after revision", len(unsupported)))
+	}
+
+	return &article, nil
+}
+
+// revisedSince reports whether a check that ran after the named check
+// revised the article.
+func revisedSince(article Article, check string) bool {
+	since := -1
+
+	for index, result := range article.Checks {
+		if result.Check == check {
+			since = index
+		}
+	}
+
+	if since < 0 {
+		return false
+	}
+
+	for _, result := range article.Checks[since+1:] {
+		if result.Revised {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unsupportedFigures returns the figures of the text that do not appear in
+// the research, allowing for differences in formatting ("1,000" and "1000",
+// "$2.5 million" and "$2,500,000", "5%" and "5 percent") and rounding.
+func unsupportedFigures(text string, research string) []string {
+	known := extractFigures(research)
+
+	var unsupported []string
+	seen := map[string]bool{}
+
+	for _, number := range extractFigures(text) {
+		// Small counts are usually spelled out in the research notes
+		// ("three people"), so they are not checked.
+		if !number.unit && number.value <= 10 {
+			continue
+		}
+
+		supported := false
+
+		for _, candidate := range known {
+			if math.Abs(number.value-candidate.value) <= number.precision/2+1e-9 {
+				supported = true
+				break
+			}
+		}
+
+		if !supported && !seen[number.text] {
+			seen[number.text] = true
+			unsupported = append(unsupported, number.text)
+		}
+	}
+
+	return unsupported
+}
+
+// extractFigures returns the figures mentioned in the text, ignoring numbers
+// that are part of dates, bare years ("the 2026 budget") and identifiers with
+// a letter prefix ("COVID-19", "G-20").
+func extractFigures(text string) []figure {
+	for _, pattern := range []*regexp.Regexp{isoDatePattern, monthDayPattern, dayMonthPattern, monthYearPattern, yearPattern} {
+		text = pattern.ReplaceAllString(text, " ")
+	}
+
+	var figures []figure
+
+	for _, indexes := range numberPattern.FindAllStringSubmatchIndex(text, -1) {
+		match := make([]string, len(indexes)/2)
+
+		for group := range match {
+			if indexes[2*group] >= 0 {
+				match[group] = text[indexes[2*group]:indexes[2*group+1]]
+			}
+		}
+
+		if hyphenated(text, indexes[4]) {
+			continue
+		}
+
+		// A bare "m" means million only after a currency symbol ("$5m");
+		// otherwise it is a distance ("the 100m final").
+		scaleWord := strings.ToLower(match[4])
+		if scaleWord == "m" && match[1] == "" {
+			scaleWord = ""
+		}
+
+		unit := match[1] != "" || scaleWord != "" || match[5] != ""
+
+		if !unit && match[3] == "" && bareYear(match[2]) {
+			continue
+		}
+
+		digits := strings.ReplaceAll(match[2], ",", "")
+
+		value, err := strconv.ParseFloat(digits+match[3], 64)
+		if err != nil {
+			continue
+		}
+
+		precision := 1.0
+		if match[3] != "" {
+			precision = math.Pow(10, -float64(len(match[3])-1))
+		}
+
+		scale := 1.0
+		if scaleWord != "" {
+			scale = numberScales[scaleWord]
+		}
+
+		figures = append(figures, figure{
+			text:      strings.TrimSpace(match[0]),
+			value:     value * scale,
+			precision: precision * scale,
+			unit:      unit,
+		})
+	}
+
+	return figures
+}
+
+// bareYear reports whether the digits of a number read as a year.
+func bareYear(digits string) bool {
+	if len(digits) != 4 {
+		return false
+	}
+
+	year, err := strconv.Atoi(digits)
+	return err == nil && year >= 1900 && year <= 2100
+}
+
+// hyphenated reports whether the number starting at start is attached to a
+// preceding word by a hyphen, as in "COVID-19" or "G-20". Ages and durations
+// such as "45-year-old" are still checked.
+func hyphenated(text string, start int) bool {
+	return start >= 2 && text[start-1] == '-' && unicode.IsLetter(rune(text[start-2]))
+}
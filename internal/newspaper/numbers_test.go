@@ -0,0 +1,154 @@
+package newspaper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsupportedFigures(t *testing.T) {
+	research := "On 2026-03-10 the agency said 1,250 homes were damaged, costing $2,480,000. " +
+		"Turnout was 61.4 percent, and 3 people were hurt. Sales reached 4.2 billion."
+
+	tests := []struct {
+		body        string
+		unsupported []string
+	}{
+		{"About 1250 homes were damaged on March 10.", nil},
+		{"Repairs will cost $2.5 million.", nil},
+		{"Turnout was 61.4%.", nil},
+		{"Turnout was 64%.", []string{"64%"}},
+		{"Two people, not 3, were hurt.", nil},
+		{"Sales reached $4.2bn, up from 3,900 units.", []string{"3,900"}},
+		{"Some 1,500 homes were damaged.", []string{"1,500"}},
+		{"The 2026 budget set aside 1,250 homes.", nil},
+		{"The 1850 census counted 1,250 homes.", []string{"1850"}},
+		{"COVID-19 cases rose at the G-20 summit.", nil},
+		{"A 45-year-old man was among 1,250 homes damaged.", []string{"45"}},
+		{"The 3-day strike damaged 1,250 homes.", nil},
+		{"She won the 100m final and 1,250 homes cheered.", []string{"100m"}},
+		{"Repairs will cost $2.5m.", nil},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.unsupported, unsupportedFigures(test.body, research), test.body)
+	}
+}
+
+func TestCheckArticleNumbersChecksDekAndKeyPoints(t *testing.T) {
+	assistant := &stubAssistant{
+		structured: func(persona, request string) (string, error) {
+			assert.Contains(t, request, `The figure "$9 million" does not appear`)
+
+			return `{"dek": "The council passed a $5 million budget.", "dateline": "SPRINGFIELD", "byline": "By the Local Desk",` +
+				` "key_points": ["The vote was unanimous."], "paragraphs": ["The council passed the budget on Monday."]}`, nil
+		},
+	}
+
+	ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{NumberCheck: true}), assistant)
+
+	article := Article{
+		Dek:       "The council passed a $9 million budget.",
+		KeyPoints: []string{"The vote was unanimous."},
+		Body:      "The council passed the budget on Monday.",
+		Research:  "On Monday the council unanimously passed a $5 million budget.",
+	}
+	article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+	checked, err := CheckArticleNumbers(ctx, article)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, assistant.structuredAsks)
+	assert.Equal(t, StatusSynthesized, checked.Status)
+	assert.Equal(t, "The council passed a $5 million budget.", checked.Dek)
+
+	require.Len(t, checked.Checks, 1)
+	assert.True(t, checked.Checks[0].Passed)
+	assert.True(t, checked.Checks[0].Revised)
+}
+
+func TestRecheckArticleNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		checks    []CheckResult
+		body      string
+		revision  string
+		revisions int
+		status    ArticleStatus
+		recheck   *CheckResult
+	}{
+		{
+			name:   "not revised",
+			checks: []CheckResult{{Check: checkArticleNumbers, Passed: true}, {Check: checkEditorReview, Passed: true}},
+			body:   "The council passed a $9 million budget.",
+			status: StatusSynthesized,
+		},
+		{
+			name:   "revised before the number check",
+			checks: []CheckResult{{Check: checkEditorReview, Passed: true, Revised: true}, {Check: checkArticleNumbers, Passed: true}},
+			body:   "The council passed a $9 million budget.",
+			status: StatusSynthesized,
+		},
+		{
+			name:    "revised with supported figures",
+			checks:  []CheckResult{{Check: checkArticleNumbers, Passed: true}, {Check: checkEditorReview, Passed: true, Revised: true}},
+			body:    "The council passed a $5 million budget.",
+			status:  StatusSynthesized,
+			recheck: &CheckResult{Check: checkArticleNumbersRecheck, Passed: true},
+		},
+		{
+			name:      "revised with unsupported figures",
+			checks:    []CheckResult{{Check: checkArticleNumbers, Passed: true}, {Check: checkArticleCopy, Passed: true, Revised: true}},
+			body:      "The council passed a $9 million budget.",
+			revision:  "The council passed a $5 million budget.",
+			revisions: 1,
+			status:    StatusSynthesized,
+			recheck:   &CheckResult{Check: checkArticleNumbersRecheck, Passed: true, Revised: true},
+		},
+		{
+			name:      "still unsupported",
+			checks:    []CheckResult{{Check: checkArticleNumbers, Passed: true}, {Check: checkFactCheck, Passed: true, Revised: true}},
+			body:      "The council passed a $9 million budget.",
+			revision:  "The council passed a $7 million budget.",
+			revisions: 1,
+			status:    StatusRejected,
+			recheck:   &CheckResult{Check: checkArticleNumbersRecheck, Revised: true, Issues: []string{"$7 million"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assistant := &stubAssistant{
+				structured: func(persona, request string) (string, error) {
+					assert.Contains(t, request, "$9 million")
+					return `{"dek": "", "key_points": [], "paragraphs": ["` + test.revision + `"]}`, nil
+				},
+			}
+
+			ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{NumberCheck: true}), assistant)
+
+			article := Article{
+				Body:     test.body,
+				Research: "On Monday the council passed a $5 million budget.",
+				Checks:   test.checks,
+			}
+			article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+			checked, err := RecheckArticleNumbers(ctx, article)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.revisions, assistant.structuredAsks)
+			assert.Equal(t, test.status, checked.Status)
+
+			if test.recheck == nil {
+				assert.Equal(t, test.checks, checked.Checks)
+				return
+			}
+
+			require.Len(t, checked.Checks, len(test.checks)+1)
+			assert.Equal(t, *test.recheck, checked.Checks[len(test.checks)])
+		})
+	}
+}
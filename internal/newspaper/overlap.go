@@ -0,0 +1,160 @@
+package newspaper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// copyNGramSize is the number of consecutive words that make up a
+	// shingle when comparing an article to its source text.
+	copyNGramSize = 8
+
+	// maxCopiedPassages limits how many copied passages are reported.
+	maxCopiedPassages = 5
+
+	checkArticleCopy = "article-copy"
+
+	CopyReviseTask = `
+		Parts of the article were copied almost word for word from the research
+		notes. Rewrite the listed passages in your own words, keeping their
+		meaning, facts, figures and attributions unchanged. Direct quotes from
+		people may stay as they are when they are attributed.
+		`
+)
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}']+`)
+
+// CheckArticleCopy measures how much of the article body was copied from
+// its research notes and facts. Articles above the copy threshold are sent
+// back for a rewrite, and rejected if they are still above it afterwards.
+func CheckArticleCopy(ctx context.Context, article Article) (*Article, error) {
+	threshold := optionsFrom(ctx).CopyThreshold
+	if !article.Status.Active() || threshold <= 0 {
+		return &article, nil
+	}
+
+	source := sourceText(article)
+	overlap, passages := copiedPassages(article.Body, source)
+	revised := false
+
+	if overlap > threshold {
+		slog.Warn("article_copy_detected",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Float64("overlap", overlap),
+			slog.Any("passages", passages),
+		)
+
+		draft, err := reviseArticle(ctx, article, CopyReviseTask, passages)
+		if err != nil {
+			slog.Warn("article_copy_revise_failed",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			article = *draft
+			revised = true
+			overlap, passages = copiedPassages(article.Body, source)
+		}
+	}
+
+	if overlap > threshold {
+		article.AddCheck(checkArticleCopy, revised, passages)
+		article.SetStatus(StageCopyCheck, StatusRejected, fmt.Sprintf("%.0f%% of the article copied from its sources", overlap*100))
+		return &article, nil
+	}
+
+	article.AddCheck(checkArticleCopy, revised, nil)
+
+	return &article, nil
+}
+
+// sourceText returns all of the source text an article was written from.
+func sourceText(article Article) string {
+	var builder strings.Builder
+
+	builder.WriteString(article.Research)
+
+	for _, fact := range article.Facts {
+		builder.WriteString("\n")
+		builder.WriteString(fact.Statement)
+	}
+
+	return builder.String()
+}
+
+// copiedPassages returns the fraction of word n-grams in the text that also
+// appear in the source, along with the longest passages they form.
+func copiedPassages(text string, source string) (float64, []string) {
+	words := wordPattern.FindAllString(text, -1)
+	if len(words) < copyNGramSize {
+		return 0, nil
+	}
+
+	sourceNGrams := map[string]bool{}
+	sourceWords := lowerWords(wordPattern.FindAllString(source, -1))
+
+	for index := 0; index+copyNGramSize <= len(sourceWords); index++ {
+		sourceNGrams[strings.Join(sourceWords[index:index+copyNGramSize], " ")] = true
+	}
+
+	lower := lowerWords(words)
+	total := len(words) - copyNGramSize + 1
+	copied := make([]bool, total)
+	matches := 0
+
+	for index := 0; index < total; index++ {
+		if sourceNGrams[strings.Join(lower[index:index+copyNGramSize], " ")] {
+			copied[index] = true
+			matches++
+		}
+	}
+
+	type run struct{ start, end int }
+	var runs []run
+
+	for index := 0; index < total; index++ {
+		if !copied[index] {
+			continue
+		}
+
+		start := index
+		for index+1 < total && copied[index+1] {
+			index++
+		}
+
+		runs = append(runs, run{start, index + copyNGramSize})
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].end-runs[i].start > runs[j].end-runs[j].start
+	})
+
+	var passages []string
+
+	for _, r := range runs {
+		if len(passages) == maxCopiedPassages {
+			break
+		}
+
+		passages = append(passages, strings.Join(words[r.start:r.end], " "))
+	}
+
+	return float64(matches) / float64(total), passages
+}
+
+func lowerWords(words []string) []string {
+	lower := make([]string, len(words))
+
+	for index, word := range words {
+		lower[index] = strings.ToLower(word)
+	}
+
+	return lower
+}
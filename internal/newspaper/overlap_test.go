@@ -0,0 +1,19 @@
+package newspaper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopiedPassages(t *testing.T) {
+	source := "The city council voted on Tuesday to approve a new budget that raises spending on public transit by a fifth."
+
+	overlap, passages := copiedPassages("The city council voted on Tuesday to approve a new budget that raises spending on public transit by a fifth.", source)
+	assert.Equal(t, 1.0, overlap)
+	assert.Len(t, passages, 1)
+
+	overlap, passages = copiedPassages("Council members backed a budget on Tuesday that lifts transit spending by about twenty percent next year.", source)
+	assert.Equal(t, 0.0, overlap)
+	assert.Empty(t, passages)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 )
 
 const (
@@ -31,26 +32,58 @@ const (
 		Section Title: {{.SectionTitle}}
 		Description: {{.SectionDescription}}
 
+		{{if .Covered}}
+		## Already Covered
+		The following stories are already covered. Do not propose them again.
+		{{range .Covered}}- {{.}}
+		{{end}}{{end}}
 		## Task
 		1. Use web searches to brainstorm candidate news stories for only this section of the newspaper
 		2. Only propose stories where the primary event/development occurred within the Date Range (inclusive)
 		3. If a story spans a longer timeline, only include it if there was a significant, date-verifiable development within the Date Range; otherwise exclude it
 		4. Avoid background/history outside the Date Range; do not select anniversary pieces, retrospectives, or "in previous years" recaps
-		5. List no more than 10 candidate stories to be used for this section
+		5. List no more than 10 candidate stories to be used for this section, most important first
 		6. For each candidate story, provide:
 			- a working headline
 			- a short description of the event (include the specific in-range date or in-range time window in the description)
+			- its importance to readers of this section on a scale of 1 (minor) to 10 (major)
+			- its format, one of: news (a straight news story), explainer (a complex story that needs context to understand), analysis (what a development means and what may come next), q-and-a (a story readers will have many questions about), timeline (a story with several developments over the Date Range), or brief (a minor story told in a few sentences)
+		7. Vary the formats across the section; use news for most stories, and brief for minor ones
 		Present the result in a clear, readable text format. Do not use any HTML, markdown, or JSON.
 		`
 )
 
 func Plan(ctx context.Context, section Section) (*[]Article, error) {
+	articles, err := planSection(ctx, section, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit := optionsFrom(ctx).ArticlesPerSection; limit > 0 && len(articles) > limit {
+		candidatesFrom(ctx).add(section.Title, articles[limit:])
+
+		slog.Info("reserved_section_articles",
+			slog.String("section", section.Title),
+			slog.Int("selected", limit),
+			slog.Int("reserved", len(articles)-limit),
+		)
+
+		articles = articles[:limit]
+	}
+
+	return &articles, nil
+}
+
+// planSection plans the stories of a section, most important first, leaving
+// out the stories already covered. Ranks continue after the covered stories.
+func planSection(ctx context.Context, section Section, covered []string) ([]Article, error) {
 	dateRange := dateRangeString(ctx)
 
 	prompt, err := BuildPrompt(SectionPlanPrompt, PromptArgs{
 		"DateRange":          dateRange,
 		"SectionTitle":       section.Title,
 		"SectionDescription": section.Description,
+		"Covered":            covered,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("generate section plan error (%s): %w", section.Title, err)
@@ -75,8 +108,17 @@ func Plan(ctx context.Context, section Section) (*[]Article, error) {
 					"type":        "string",
 					"description": "summary of the article",
 				},
+				"importance": map[string]any{
+					"type":        "integer",
+					"description": "importance of the article from 1 (minor) to 10 (major)",
+				},
+				"format": map[string]any{
+					"type":        "string",
+					"enum":        articleFormats,
+					"description": "format of the article",
+				},
 			},
-			"required": []string{"headline", "summary"},
+			"required": []string{"headline", "summary", "importance", "format"},
 		},
 	}
 
@@ -98,14 +140,30 @@ func Plan(ctx context.Context, section Section) (*[]Article, error) {
 	}
 
 	for index := 0; index < len(articles); index++ {
-		articles[index].Valid = true
+		articles[index].SetStatus(StagePlan, StatusPlanned, "")
 		articles[index].Section = section
+		articles[index].Importance = min(max(articles[index].Importance, minImportance), maxImportance)
+
+		if _, ok := formatSpecs[articles[index].Format]; !ok {
+			articles[index].Format = FormatNews
+		}
 
 		slog.Info("generated_section_article",
 			slog.String("section", section.Title),
 			slog.Any("headline", articles[index].Headline),
+			slog.Int("importance", articles[index].Importance),
+			slog.String("format", string(articles[index].Format)),
 		)
 	}
 
-	return &articles, nil
+	// Most important first, keeping the planner's order for equal importance.
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].Importance > articles[j].Importance
+	})
+
+	for index := range articles {
+		articles[index].Rank = len(covered) + index
+	}
+
+	return articles, nil
 }
@@ -0,0 +1,130 @@
+package newspaper
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// SourcePolicy restricts which web sites research may draw on. Domains match
+// themselves and any of their subdomains.
+type SourcePolicy struct {
+	// AllowedDomains, when not empty, is the only set of domains facts may
+	// be sourced from.
+	AllowedDomains []string
+	// BlockedDomains are never used as sources.
+	BlockedDomains []string
+	// PreferredOutlets are suggested to the researcher as sources to favor.
+	PreferredOutlets []string
+}
+
+// sourcePolicy returns the policy for a section, combining the edition policy
+// with the section policy. Blocked domains and preferred outlets from both are
+// used, while allowed domains set on the section replace those of the edition.
+func sourcePolicy(ctx context.Context, section Section) SourcePolicy {
+	edition := optionsFrom(ctx).SourcePolicy
+
+	policy := SourcePolicy{
+		AllowedDomains:   edition.AllowedDomains,
+		BlockedDomains:   append(append([]string{}, edition.BlockedDomains...), section.SourcePolicy.BlockedDomains...),
+		PreferredOutlets: append(append([]string{}, edition.PreferredOutlets...), section.SourcePolicy.PreferredOutlets...),
+	}
+
+	if len(section.SourcePolicy.AllowedDomains) > 0 {
+		policy.AllowedDomains = section.SourcePolicy.AllowedDomains
+	}
+
+	return policy
+}
+
+// Violation returns a description of why the policy does not permit the
+// source URL, or an empty string when it does. A restrictive policy does not
+// permit sources without a URL, since they cannot be checked.
+func (p SourcePolicy) Violation(sourceURL string) string {
+	if !p.restrictive() {
+		return ""
+	}
+
+	host := sourceHost(sourceURL)
+	if host == "" {
+		return "unknown domain"
+	}
+
+	for _, domain := range p.BlockedDomains {
+		if matchesDomain(host, domain) {
+			return "blocked domain " + normalizeDomain(domain)
+		}
+	}
+
+	if len(p.AllowedDomains) == 0 {
+		return ""
+	}
+
+	for _, domain := range p.AllowedDomains {
+		if matchesDomain(host, domain) {
+			return ""
+		}
+	}
+
+	return "domain " + host + " not allowed"
+}
+
+// restrictive reports whether the policy rules out any sources, so research
+// must name its sources to be checked against it.
+func (p SourcePolicy) restrictive() bool {
+	return len(p.AllowedDomains) > 0 || len(p.BlockedDomains) > 0
+}
+
+// prompt returns the policy as instructions for the researcher, or an empty
+// string when there is no policy.
+func (p SourcePolicy) prompt() string {
+	var builder strings.Builder
+
+	if len(p.AllowedDomains) > 0 {
+		builder.WriteString("- Only use sources from these domains: " + strings.Join(p.AllowedDomains, ", ") + "\n")
+	}
+
+	if len(p.BlockedDomains) > 0 {
+		builder.WriteString("- Never use sources from these domains: " + strings.Join(p.BlockedDomains, ", ") + "\n")
+	}
+
+	if len(p.PreferredOutlets) > 0 {
+		builder.WriteString("- Prefer these outlets when they cover the event: " + strings.Join(p.PreferredOutlets, ", ") + "\n")
+	}
+
+	return builder.String()
+}
+
+func sourceHost(sourceURL string) string {
+	sourceURL = strings.TrimSpace(sourceURL)
+	if sourceURL == "" {
+		return ""
+	}
+
+	if !strings.Contains(sourceURL, "://") {
+		sourceURL = "https://" + sourceURL
+	}
+
+	parsed, err := url.Parse(sourceURL)
+	if err != nil {
+		return ""
+	}
+
+	return normalizeDomain(parsed.Hostname())
+}
+
+func normalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimSuffix(domain, ".")
+
+	return strings.TrimPrefix(domain, "www.")
+}
+
+func matchesDomain(host string, domain string) bool {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return false
+	}
+
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,81 @@
+package newspaper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourcePolicyViolation(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    SourcePolicy
+		source    string
+		violation string
+	}{
+		{"no policy", SourcePolicy{}, "https://example.com/story", ""},
+		{"no policy unknown domain", SourcePolicy{}, "", ""},
+		{"blocked unknown domain", SourcePolicy{BlockedDomains: []string{"tabloid.com"}}, "", "unknown domain"},
+		{"blocked", SourcePolicy{BlockedDomains: []string{"tabloid.com"}}, "https://tabloid.com/a", "blocked domain tabloid.com"},
+		{"blocked subdomain", SourcePolicy{BlockedDomains: []string{"tabloid.com"}}, "https://news.tabloid.com/a", "blocked domain tabloid.com"},
+		{"blocked www", SourcePolicy{BlockedDomains: []string{"www.Tabloid.com"}}, "tabloid.com/a", "blocked domain tabloid.com"},
+		{"blocked suffix only", SourcePolicy{BlockedDomains: []string{"tabloid.com"}}, "https://nottabloid.com/a", ""},
+		{"allowed", SourcePolicy{AllowedDomains: []string{"reuters.com"}}, "https://www.reuters.com/a", ""},
+		{"allowed subdomain", SourcePolicy{AllowedDomains: []string{"reuters.com"}}, "https://uk.reuters.com/a", ""},
+		{"not allowed", SourcePolicy{AllowedDomains: []string{"reuters.com"}}, "https://apnews.com/a", "domain apnews.com not allowed"},
+		{"allowed unknown domain", SourcePolicy{AllowedDomains: []string{"reuters.com"}}, "", "unknown domain"},
+		{"blocked and allowed", SourcePolicy{AllowedDomains: []string{"reuters.com"}, BlockedDomains: []string{"reuters.com"}}, "https://reuters.com/a", "blocked domain reuters.com"},
+		{"blocked subdomain of allowed", SourcePolicy{AllowedDomains: []string{"example.com"}, BlockedDomains: []string{"blog.example.com"}}, "https://blog.example.com/a", "blocked domain blog.example.com"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.violation, test.policy.Violation(test.source), test.name)
+	}
+}
+
+func TestSectionSourcePolicy(t *testing.T) {
+	ctx := withOptions(context.Background(), NewspaperOptions{
+		SourcePolicy: SourcePolicy{
+			AllowedDomains:   []string{"reuters.com"},
+			BlockedDomains:   []string{"tabloid.com"},
+			PreferredOutlets: []string{"apnews.com"},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		section SourcePolicy
+		policy  SourcePolicy
+	}{
+		{
+			name: "edition policy",
+			policy: SourcePolicy{
+				AllowedDomains:   []string{"reuters.com"},
+				BlockedDomains:   []string{"tabloid.com"},
+				PreferredOutlets: []string{"apnews.com"},
+			},
+		},
+		{
+			name: "section allowed domains replace the edition",
+			section: SourcePolicy{
+				AllowedDomains:   []string{"bbc.co.uk"},
+				BlockedDomains:   []string{"gossip.com"},
+				PreferredOutlets: []string{"bbc.co.uk"},
+			},
+			policy: SourcePolicy{
+				AllowedDomains:   []string{"bbc.co.uk"},
+				BlockedDomains:   []string{"tabloid.com", "gossip.com"},
+				PreferredOutlets: []string{"apnews.com", "bbc.co.uk"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.policy, sourcePolicy(ctx, Section{SourcePolicy: test.section}), test.name)
+	}
+
+	policy := sourcePolicy(ctx, Section{SourcePolicy: SourcePolicy{AllowedDomains: []string{"bbc.co.uk"}}})
+	assert.Equal(t, "domain reuters.com not allowed", policy.Violation("https://reuters.com/a"))
+	assert.Empty(t, policy.Violation("https://www.bbc.co.uk/news"))
+}
@@ -0,0 +1,100 @@
+package newspaper
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const checkResearchInjection = "research-injection"
+
+var (
+	// hiddenTextPattern matches text a reader of the page would not see:
+	// HTML comments, script and style blocks, and invisible characters.
+	hiddenTextPattern = regexp.MustCompile(`(?is)<!--.*?-->|<script\b.*?</script>|<style\b.*?</style>|[\x{200B}-\x{200F}\x{202A}-\x{202E}\x{2060}-\x{2064}\x{2066}-\x{2069}\x{FEFF}]`)
+
+	// roleMarkerPattern matches chat role markers and model control tokens.
+	roleMarkerPattern = regexp.MustCompile(`(?i)<\|[a-z_]+\|>|\[/?INST\]|<</?SYS>>|</?s>|(?m:^\s*(?:#+\s*)?(?:system|assistant|user|developer|human|ai)\s*:)`)
+
+	// instructionPattern matches text addressed to a language model rather
+	// than to a reader of the news.
+	instructionPattern = regexp.MustCompile(`(?i)\b(?:` +
+		`(?:ignore|disregard|forget|override|bypass)\s+(?:all\s+|any\s+|the\s+|your\s+|these\s+|of\s+)*(?:previous|prior|above|earlier|preceding|system)\s+(?:instructions|prompts?)` +
+		`|(?:ignore|disregard|forget)\s+(?:all|any)\s+(?:of\s+)?(?:your\s+|the\s+)?(?:instructions|prompts?)\b` +
+		`|new\s+instructions\s*:` +
+		`|(?:system|developer|hidden)\s+prompt` +
+		`|as\s+an\s+ai(?:\s+language)?\s+model` +
+		`|(?:language\s+model|llm|ai\s+assistant|chatbot)s?,?\s+(?:must|should|reading\s+this)` +
+		`|(?:do\s+not|don't|never)\s+(?:tell|reveal|mention\s+to)\s+the\s+user` +
+		`|(?:respond|reply|answer)\s+only\s+with` +
+		`|(?:when|if)\s+(?:writing|summari[sz]ing)\s+(?:this|the)\s+(?:article|page|content)` +
+		`|include\s+the\s+following\s+(?:link|url|text|message)\s+in` +
+		`)`)
+)
+
+// QuarantineResearch neutralizes content in the research notes that tries to
+// instruct the assistant instead of informing the reader, so hostile web
+// pages cannot steer synthesis. Hidden text and role markers are removed, and
+// sentences and facts containing instructions are dropped.
+func QuarantineResearch(ctx context.Context, article Article) (*Article, error) {
+	if !article.Status.Active() {
+		return &article, nil
+	}
+
+	var quarantined []string
+
+	research := hiddenTextPattern.ReplaceAllStringFunc(article.Research, func(match string) string {
+		quarantined = append(quarantined, "hidden text "+strings.TrimSpace(match))
+		return ""
+	})
+
+	research = roleMarkerPattern.ReplaceAllStringFunc(research, func(match string) string {
+		quarantined = append(quarantined, "role marker "+strings.TrimSpace(match))
+		return ""
+	})
+
+	research, instructions := stripSentences(research, func(sentence string) (string, bool) {
+		if !instructionPattern.MatchString(sentence) {
+			return "", false
+		}
+
+		return "instruction " + sentence, true
+	})
+
+	quarantined = append(quarantined, instructions...)
+
+	var facts []Fact
+
+	for _, fact := range article.Facts {
+		if instructionPattern.MatchString(fact.Statement) || roleMarkerPattern.MatchString(fact.Statement) {
+			quarantined = append(quarantined, "fact "+fact.Statement)
+			continue
+		}
+
+		fact.Statement = hiddenTextPattern.ReplaceAllString(fact.Statement, "")
+		facts = append(facts, fact)
+	}
+
+	if len(quarantined) == 0 {
+		article.AddCheck(checkResearchInjection, false, nil)
+		return &article, nil
+	}
+
+	slog.Warn("research_quarantined",
+		slog.String("section", article.Section.Title),
+		slog.String("headline", article.Headline),
+		slog.Any("quarantined", quarantined),
+	)
+
+	article.Research = research
+	article.Facts = facts
+	article.Sources = factSources(facts)
+	article.AddCheck(checkResearchInjection, true, nil)
+
+	if strings.TrimSpace(article.Research) == "" {
+		article.SetStatus(StageQuarantine, StatusInsufficient, "nothing left in the research notes after quarantining instructions")
+	}
+
+	return &article, nil
+}
@@ -0,0 +1,77 @@
+package newspaper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/schraf/assistant/pkg/models"
+)
+
+// sourcesHeading introduces the list of sources after an article.
+const sourcesHeading = "Sources:"
+
+// keyPointsHeading introduces the key points of an article.
+const keyPointsHeading = "Key points:"
+
+// addArticle renders an article as a new section of the document: the dek,
+// byline and key points, followed by the paragraphs of the body with the
+// dateline leading the first one. The body is split on blank lines as is,
+// since it was already normalized into paragraphs.
+func addArticle(ctx context.Context, doc *models.Document, article Article) {
+	var paragraphs []string
+
+	for _, paragraph := range strings.Split(article.Body, "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+
+	if article.Dateline != "" && len(paragraphs) > 0 {
+		paragraphs[0] = article.Dateline + " — " + paragraphs[0]
+	}
+
+	doc.Sections = append(doc.Sections, models.DocumentSection{Title: article.Headline})
+	section := &doc.Sections[len(doc.Sections)-1]
+
+	var header []string
+
+	if article.Dek != "" {
+		header = append(header, article.Dek)
+	}
+
+	if article.Byline != "" {
+		header = append(header, article.Byline)
+	}
+
+	if len(article.KeyPoints) > 0 {
+		header = append(header, keyPointsHeading)
+
+		for _, point := range article.KeyPoints {
+			header = append(header, "• "+strings.TrimSpace(point))
+		}
+	}
+
+	section.Paragraphs = append(header, paragraphs...)
+
+	if optionsFrom(ctx).IncludeSources && len(article.Sources) > 0 {
+		section.Paragraphs = append(section.Paragraphs, sourcesHeading)
+
+		for index, source := range article.Sources {
+			section.Paragraphs = append(section.Paragraphs, sourceCitation(index+1, source))
+		}
+	}
+}
+
+// sourceCitation formats a source as a numbered citation. The numbering uses
+// brackets so it is not mistaken for a Markdown list when the document is cleaned.
+func sourceCitation(number int, source Source) string {
+	switch {
+	case source.Name == "":
+		return fmt.Sprintf("[%d] %s", number, source.URL)
+	case source.URL == "":
+		return fmt.Sprintf("[%d] %s", number, source.Name)
+	default:
+		return fmt.Sprintf("[%d] %s, %s", number, source.Name, source.URL)
+	}
+}
@@ -0,0 +1,82 @@
+package newspaper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/schraf/assistant/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddArticle(t *testing.T) {
+	article := Article{
+		Headline:  "Council passes budget",
+		Dek:       "The plan funds two new schools.",
+		Byline:    "By the Local Desk",
+		Dateline:  "SPRINGFIELD, March 10",
+		KeyPoints: []string{"The vote was 5 to 2.", " Work starts in May. "},
+		Body:      "The council passed the budget on Monday.\n\n\n\nIt takes effect in July.\n\n",
+		Sources:   []Source{{Name: "Springfield Times", URL: "https://times.example.com/budget"}},
+	}
+
+	var doc models.Document
+	addArticle(context.Background(), &doc, article)
+
+	require.Len(t, doc.Sections, 1)
+	assert.Equal(t, "Council passes budget", doc.Sections[0].Title)
+	assert.Equal(t, []string{
+		"The plan funds two new schools.",
+		"By the Local Desk",
+		keyPointsHeading,
+		"• The vote was 5 to 2.",
+		"• Work starts in May.",
+		"SPRINGFIELD, March 10 — The council passed the budget on Monday.",
+		"It takes effect in July.",
+	}, doc.Sections[0].Paragraphs)
+}
+
+func TestAddArticleWithoutHeader(t *testing.T) {
+	var doc models.Document
+	addArticle(context.Background(), &doc, Article{Headline: "Council passes budget", Body: "The council passed the budget."})
+
+	require.Len(t, doc.Sections, 1)
+	assert.Equal(t, []string{"The council passed the budget."}, doc.Sections[0].Paragraphs)
+}
+
+func TestAddArticleSources(t *testing.T) {
+	article := Article{
+		Headline: "Council passes budget",
+		Body:     "The council passed the budget.",
+		Sources: []Source{
+			{Name: "Springfield Times", URL: "https://times.example.com/budget"},
+			{Name: "City Council"},
+			{URL: "https://example.org/minutes"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		include    bool
+		paragraphs []string
+	}{
+		{"excluded", false, []string{"The council passed the budget."}},
+		{"included", true, []string{
+			"The council passed the budget.",
+			sourcesHeading,
+			"[1] Springfield Times, https://times.example.com/budget",
+			"[2] City Council",
+			"[3] https://example.org/minutes",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var doc models.Document
+			addArticle(withOptions(context.Background(), NewspaperOptions{IncludeSources: test.include}), &doc, article)
+
+			require.Len(t, doc.Sections, 1)
+			assert.Equal(t, test.paragraphs, doc.Sections[0].Paragraphs)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package newspaper
+
+// ArticleReport summarizes what happened to a single article of an edition.
+type ArticleReport struct {
+	Section   string        `json:"section"`
+	Headline  string        `json:"headline"`
+	Format    ArticleFormat `json:"format,omitempty"`
+	Placement Placement     `json:"placement,omitempty"`
+	Published bool          `json:"published"`
+	Status    ArticleStatus `json:"status"`
+	Stage     Stage         `json:"stage"`
+	Reason    string        `json:"reason,omitempty"`
+	Checks    []CheckResult `json:"checks,omitempty"`
+}
+
+// Report summarizes every article of the edition, published articles first
+// and then the dropped articles.
+func (e Edition) Report() []ArticleReport {
+	reports := make([]ArticleReport, 0, len(e.Articles)+len(e.Dropped))
+
+	for _, article := range e.Articles {
+		reports = append(reports, articleReport(article, true))
+	}
+
+	for _, article := range e.Dropped {
+		reports = append(reports, articleReport(article, false))
+	}
+
+	return reports
+}
+
+func articleReport(article Article, published bool) ArticleReport {
+	return ArticleReport{
+		Section:   article.Section.Title,
+		Headline:  article.Headline,
+		Format:    article.Format,
+		Placement: article.Placement,
+		Published: published,
+		Status:    article.Status,
+		Stage:     article.StatusStage,
+		Reason:    article.StatusReason,
+		Checks:    article.Checks,
+	}
+}
@@ -2,9 +2,11 @@ package newspaper
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/schraf/assistant/pkg/models"
 )
@@ -21,6 +23,20 @@ const (
 		date is within range, omit it.
 		`
 
+	ResearchFactualSystemPrompt = `
+		You are a wire-service fact checker. Your sole task is to search the
+		web and compile a neutral, strictly factual record of a public news
+		event for a newspaper of record, based on official statements, court
+		and public records, and reporting by established news organizations.
+		You report what happened without graphic detail, speculation, or
+		opinion.
+
+		The user will provide a Date Range. Treat it as a hard constraint:
+		only gather and report events, developments, and data points that
+		occurred within the Date Range (inclusive). If you cannot verify the
+		date is within range, omit it.
+		`
+
 	ResearchPrompt = `
 		## Date Range (inclusive, UTC)
 		{{.DateRange}}
@@ -45,26 +61,168 @@ const (
 		- If sources discuss background/history outside the Date Range, do not include it.
 		- If a claim is undated or the date is ambiguous, omit it.
 		- Prefer sources that explicitly state dates within the Date Range.
+		{{if .SourcePolicy}}
+		## Source Policy (do not violate)
+		{{.SourcePolicy}}{{end}}
+		## Output Requirements
+		- Plain text only (no HTML, no Markdown).
+		- Include the in-range dates next to key facts/numbers.
+		- If you cannot find enough in-range information to support the story, say so explicitly.
+		- Name the source (outlet and URL) each fact was taken from.
+		`
+
+	ResearchFactualPrompt = `
+		## Date Range (inclusive, UTC)
+		{{.DateRange}}
 
+		## Newspaper Section
+		{{.Section}}
+
+		## Article Headline
+		{{.Headline}}
+
+		## Event Summary
+		{{.Summary}}
+
+		## Goal
+		Compile the public factual record of this news event as it would be
+		reported by a wire service: who was involved, what happened, when and
+		where it happened, what officials and the organizations involved have
+		said, and what happens next. Describe sensitive matters (crime,
+		conflict, health) in plain, clinical terms without graphic detail.
+
+		## Hard Rules (do not violate)
+		- Only include facts/events/data that occurred within the Date Range (inclusive).
+		- Attribute every claim to an official statement, public record, or established news organization.
+		- Do not include graphic descriptions, speculation, or opinion.
+		- If a claim is undated or the date is ambiguous, omit it.
+		{{if .SourcePolicy}}
+		## Source Policy (do not violate)
+		{{.SourcePolicy}}{{end}}
 		## Output Requirements
 		- Plain text only (no HTML, no Markdown).
 		- Include the in-range dates next to key facts/numbers.
 		- If you cannot find enough in-range information to support the story, say so explicitly.
+		- Name the source (outlet and URL) each fact was taken from.
+		`
+
+	ResearchGapsPrompt = `
+		## Date Range (inclusive, UTC)
+		{{.DateRange}}
+
+		## Article Headline
+		{{.Headline}}
+
+		## Event Summary
+		{{.Summary}}
+
+		## Research Notes
+		{{.Research}}
+
+		## Task
+		Review the research notes for a news article about this event and decide
+		whether they are complete enough to write the article. Look for missing
+		elements: who was involved, what happened, when and where it happened,
+		key numbers and figures, and reactions from the people and organizations
+		affected. If anything important is missing, write up to 3 targeted
+		research questions that would fill the gaps.
+		`
+
+	ResearchFollowUpPrompt = `
+		## Date Range (inclusive, UTC)
+		{{.DateRange}}
+
+		## Newspaper Section
+		{{.Section}}
+
+		## Article Headline
+		{{.Headline}}
+
+		## Event Summary
+		{{.Summary}}
+
+		## Research Questions
+		{{range .Questions}}- {{.}}
+		{{end}}
+		## Goal
+		Search the web and answer each of the research questions about this
+		event. Only report new information that answers the questions.
+
+		## Hard Rules (do not violate)
+		- Only include facts/events/data that occurred within the Date Range (inclusive).
+		- If a claim is undated or the date is ambiguous, omit it.
+		{{if .SourcePolicy}}
+		## Source Policy (do not violate)
+		{{.SourcePolicy}}{{end}}
+		## Output Requirements
+		- Plain text only (no HTML, no Markdown).
+		- Include the in-range dates next to key facts/numbers.
+		- Name the source (outlet and URL) each fact was taken from.
+		`
+
+	ResearchFactsPrompt = `
+		## Date Range (inclusive, UTC)
+		{{.DateRange}}
+
+		## Research Notes
+		{{.Research}}
+
+		## Task
+		Extract the list of facts from the research notes. For each fact provide:
+			- the statement of the fact as a single sentence
+			- the date the fact occurred in ISO-8601 format (YYYY-MM-DD), or an empty string if undated
+			- the name of the source outlet
+			- the URL of the source
+			- your confidence that the fact is accurate and within the Date Range (high, medium or low)
+		Only extract facts that appear in the research notes. Do not add new information.
 		`
 )
 
 func ResearchArticle(ctx context.Context, article Article) (*Article, error) {
-	prompt, err := BuildPrompt(ResearchPrompt, PromptArgs{
-		"DateRange": dateRangeString(ctx),
-		"Section":   article.Section.Title,
-		"Headline":  article.Headline,
-		"Summary":   article.Summary,
-	})
+	if loadCachedResearch(ctx, &article) {
+		article.SetStatus(StageResearch, StatusResearched, "loaded from research cache")
+
+		slog.Info("research_cache_hit",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Int("research", len(article.Research)),
+			slog.Int("facts", len(article.Facts)),
+		)
+
+		return &article, nil
+	}
+
+	policy := sourcePolicy(ctx, article.Section)
+
+	promptArgs := PromptArgs{
+		"DateRange":    dateRangeString(ctx),
+		"Section":      article.Section.Title,
+		"Headline":     article.Headline,
+		"Summary":      article.Summary,
+		"SourcePolicy": policy.prompt(),
+	}
+
+	prompt, err := BuildPrompt(ResearchPrompt, promptArgs)
 	if err != nil {
 		return nil, fmt.Errorf("research prompt error: %w", err)
 	}
 
 	research, err := ask(ctx, ResearchSystemPrompt, *prompt)
+
+	if errors.Is(err, models.ErrContentBlocked) && optionsFrom(ctx).RetryBlockedResearch {
+		slog.Warn("research_content_blocked_retry",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+		)
+
+		prompt, err = BuildPrompt(ResearchFactualPrompt, promptArgs)
+		if err != nil {
+			return nil, fmt.Errorf("research factual prompt error: %w", err)
+		}
+
+		research, err = ask(ctx, ResearchFactualSystemPrompt, *prompt)
+	}
+
 	if err != nil {
 		if errors.Is(err, models.ErrContentBlocked) {
 			slog.Warn("research_content_blocked",
@@ -72,7 +230,22 @@ func ResearchArticle(ctx context.Context, article Article) (*Article, error) {
 				slog.String("headline", article.Headline),
 			)
 
-			article.Valid = false
+			article.SetStatus(StageResearch, StatusBlocked, "research content blocked")
+
+			if optionsFrom(ctx).SubstituteBlockedStories {
+				if substitute, ok := candidatesFrom(ctx).next(article.Section.Title); ok {
+					slog.Info("research_substituted",
+						slog.String("section", article.Section.Title),
+						slog.String("headline", article.Headline),
+						slog.String("substitute", substitute.Headline),
+					)
+
+					article.StatusReason += "; replaced by " + substitute.Headline
+					candidatesFrom(ctx).replace(article)
+
+					return ResearchArticle(ctx, substitute)
+				}
+			}
 		} else {
 			slog.Warn("research_failed",
 				slog.String("section", article.Section.Title),
@@ -80,27 +253,328 @@ func ResearchArticle(ctx context.Context, article Article) (*Article, error) {
 				slog.String("error", err.Error()),
 			)
 
-			article.Valid = false
+			article.SetStatus(StageResearch, StatusFailed, "research failed: "+err.Error())
 		}
-	} else {
-		if len(*research) == 0 {
-			slog.Warn("empty_research",
+
+		return &article, nil
+	}
+
+	if len(*research) == 0 {
+		slog.Warn("empty_research",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+		)
+
+		article.SetStatus(StageResearch, StatusInsufficient, "research returned no notes")
+		return &article, nil
+	}
+
+	article.Research = *research
+
+	for round := 1; round <= optionsFrom(ctx).ResearchDepth; round++ {
+		followUp, err := researchFollowUp(ctx, article)
+		if err != nil {
+			slog.Warn("research_follow_up_failed",
 				slog.String("section", article.Section.Title),
 				slog.String("headline", article.Headline),
+				slog.Int("round", round),
+				slog.String("error", err.Error()),
 			)
 
-			article.Valid = false
-		} else {
-			article.Valid = true
-			article.Research = *research
+			break
+		}
+
+		if followUp == nil {
+			break
+		}
+
+		article.Research += "\n\n" + *followUp
 
-			slog.Info("researched_article",
+		slog.Info("research_follow_up",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Int("round", round),
+			slog.Int("research", len(article.Research)),
+		)
+	}
+
+	facts, err := extractFacts(ctx, article.Research)
+	if err != nil {
+		slog.Warn("research_facts_failed",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	// Without sources there is nothing to check the research against, so a
+	// restrictive policy cannot be honored.
+	if policy.restrictive() && !hasSourceURL(facts) {
+		slog.Warn("research_sources_unknown",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Int("facts", len(facts)),
+		)
+
+		article.SetStatus(StageResearch, StatusBlocked, "no sources could be extracted to check against the source policy")
+		return &article, nil
+	}
+
+	article.Facts = nil
+	policyViolations := 0
+
+	for _, fact := range facts {
+		if date, ok := fact.Time(); ok && !inDateRange(ctx, date) {
+			slog.Warn("research_fact_out_of_range",
 				slog.String("section", article.Section.Title),
 				slog.String("headline", article.Headline),
-				slog.Int("research", len(article.Research)),
+				slog.String("date", fact.Date),
+				slog.String("statement", fact.Statement),
 			)
+
+			continue
+		}
+
+		if violation := policy.Violation(fact.SourceURL); violation != "" {
+			slog.Warn("research_source_policy_violation",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.String("source", fact.SourceURL),
+				slog.String("violation", violation),
+				slog.String("statement", fact.Statement),
+			)
+
+			policyViolations++
+			continue
+		}
+
+		article.Facts = append(article.Facts, fact)
+	}
+
+	// The research notes may contain text from sources the policy does not
+	// permit, attributed or not, so rebuild them from the permitted facts.
+	if policy.restrictive() {
+		article.Research = factNotes(article.Facts)
+
+		if len(article.Research) == 0 {
+			slog.Warn("research_no_permitted_sources",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.Int("violations", policyViolations),
+			)
+
+			article.SetStatus(StageResearch, StatusBlocked, fmt.Sprintf("all %d facts came from sources the source policy does not permit", policyViolations))
+			return &article, nil
 		}
 	}
 
+	article.Sources = factSources(article.Facts)
+	article.SetStatus(StageResearch, StatusResearched, "")
+
+	slog.Info("researched_article",
+		slog.String("section", article.Section.Title),
+		slog.String("headline", article.Headline),
+		slog.Int("research", len(article.Research)),
+		slog.Int("facts", len(article.Facts)),
+		slog.Int("sources", len(article.Sources)),
+	)
+
+	storeCachedResearch(ctx, article)
+
 	return &article, nil
 }
+
+// researchFollowUp analyzes the research gathered so far for missing
+// elements and researches targeted follow-up questions. It returns nil when
+// the research is already complete.
+func researchFollowUp(ctx context.Context, article Article) (*string, error) {
+	prompt, err := BuildPrompt(ResearchGapsPrompt, PromptArgs{
+		"DateRange": dateRangeString(ctx),
+		"Headline":  article.Headline,
+		"Summary":   article.Summary,
+		"Research":  article.Research,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("research gaps prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"complete": map[string]any{
+				"type":        "boolean",
+				"description": "true if the research notes are complete enough to write the article",
+			},
+			"questions": map[string]any{
+				"type":        "array",
+				"description": "targeted research questions that would fill the gaps",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+		},
+		"required": []string{"complete", "questions"},
+	}
+
+	responseJson, err := structuredAsk(ctx, ResearchSystemPrompt, *prompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("research gaps structured ask: %w", err)
+	}
+
+	var gaps struct {
+		Complete  bool
+		Questions []string
+	}
+
+	if err := json.Unmarshal(responseJson, &gaps); err != nil {
+		return nil, fmt.Errorf("research gaps unmarshal json: %w", err)
+	}
+
+	if gaps.Complete || len(gaps.Questions) == 0 {
+		return nil, nil
+	}
+
+	prompt, err = BuildPrompt(ResearchFollowUpPrompt, PromptArgs{
+		"DateRange":    dateRangeString(ctx),
+		"Section":      article.Section.Title,
+		"Headline":     article.Headline,
+		"Summary":      article.Summary,
+		"Questions":    gaps.Questions,
+		"SourcePolicy": sourcePolicy(ctx, article.Section).prompt(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("research follow up prompt error: %w", err)
+	}
+
+	research, err := ask(ctx, ResearchSystemPrompt, *prompt)
+	if err != nil {
+		return nil, fmt.Errorf("research follow up ask: %w", err)
+	}
+
+	if len(*research) == 0 {
+		return nil, nil
+	}
+
+	return research, nil
+}
+
+// extractFacts converts free-form research notes into a list of dated,
+// sourced facts.
+func extractFacts(ctx context.Context, research string) ([]Fact, error) {
+	prompt, err := BuildPrompt(ResearchFactsPrompt, PromptArgs{
+		"DateRange": dateRangeString(ctx),
+		"Research":  research,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("research facts prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type":        "array",
+		"description": "list of facts",
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"statement": map[string]any{
+					"type":        "string",
+					"description": "the fact as a single sentence",
+				},
+				"date": map[string]any{
+					"type":        "string",
+					"description": "date of the fact (YYYY-MM-DD), empty if undated",
+				},
+				"source_name": map[string]any{
+					"type":        "string",
+					"description": "name of the source outlet",
+				},
+				"source_url": map[string]any{
+					"type":        "string",
+					"description": "URL of the source",
+				},
+				"confidence": map[string]any{
+					"type":        "string",
+					"enum":        []string{string(ConfidenceHigh), string(ConfidenceMedium), string(ConfidenceLow)},
+					"description": "confidence that the fact is accurate and in range",
+				},
+			},
+			"required": []string{"statement", "date", "source_name", "source_url", "confidence"},
+		},
+	}
+
+	responseJson, err := structuredAsk(ctx, ResearchSystemPrompt, *prompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("research facts structured ask: %w", err)
+	}
+
+	var facts []Fact
+
+	if err := json.Unmarshal(responseJson, &facts); err != nil {
+		return nil, fmt.Errorf("research facts unmarshal json: %w", err)
+	}
+
+	return facts, nil
+}
+
+// factNotes renders facts as plain text research notes.
+func factNotes(facts []Fact) string {
+	var builder strings.Builder
+
+	for _, fact := range facts {
+		builder.WriteString(fact.Statement)
+
+		if fact.Date != "" {
+			builder.WriteString(" (" + fact.Date + ")")
+		}
+
+		if fact.SourceName != "" || fact.SourceURL != "" {
+			builder.WriteString(" Source: " + strings.TrimSpace(fact.SourceName+" "+fact.SourceURL))
+		}
+
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// hasSourceURL reports whether any of the facts names the URL of its source.
+func hasSourceURL(facts []Fact) bool {
+	for _, fact := range facts {
+		if sourceHost(fact.SourceURL) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// factSources returns the distinct sources cited by the facts, in the order
+// they first appear.
+func factSources(facts []Fact) []Source {
+	var sources []Source
+	seen := map[string]bool{}
+
+	for _, fact := range facts {
+		source := Source{
+			Name: strings.TrimSpace(fact.SourceName),
+			URL:  strings.TrimSpace(fact.SourceURL),
+		}
+
+		if source.Name == "" && source.URL == "" {
+			continue
+		}
+
+		key := strings.ToLower(source.URL)
+		if key == "" {
+			key = strings.ToLower(source.Name)
+		}
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		sources = append(sources, source)
+	}
+
+	return sources
+}
@@ -0,0 +1,256 @@
+package newspaper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/schraf/assistant/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAssistant answers asks with canned responses and counts the calls it
+// gets. A nil answer function fails the call. Calls are answered one at a
+// time, so the answer functions may keep state.
+type stubAssistant struct {
+	answer     func(persona string, request string) (string, error)
+	structured func(persona string, request string) (string, error)
+
+	mu             sync.Mutex
+	asks           int
+	structuredAsks int
+}
+
+func (s *stubAssistant) Ask(ctx context.Context, persona string, request string) (*string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.asks++
+
+	if s.answer == nil {
+		return nil, assert.AnError
+	}
+
+	response, err := s.answer(persona, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (s *stubAssistant) StructuredAsk(ctx context.Context, persona string, request string, schema map[string]any) (json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.structuredAsks++
+
+	if s.structured == nil {
+		return nil, assert.AnError
+	}
+
+	response, err := s.structured(persona, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(response), nil
+}
+
+func (s *stubAssistant) WithModel(ctx context.Context, model string) context.Context {
+	return ctx
+}
+
+func TestResearchFollowUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		gaps     string
+		followUp bool
+	}{
+		{"complete", `{"complete": true, "questions": ["Who voted against it?"]}`, false},
+		{"no questions", `{"complete": false, "questions": []}`, false},
+		{"questions", `{"complete": false, "questions": ["Who voted against it?"]}`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assistant := &stubAssistant{
+				structured: func(persona, request string) (string, error) {
+					return test.gaps, nil
+				},
+				answer: func(persona, request string) (string, error) {
+					return "Three members voted against the budget.", nil
+				},
+			}
+
+			ctx := withAssistant(context.Background(), assistant)
+			research, err := researchFollowUp(ctx, Article{Headline: "Council passes budget", Research: "The council passed the budget."})
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, assistant.structuredAsks)
+
+			if !test.followUp {
+				assert.Nil(t, research)
+				assert.Zero(t, assistant.asks)
+				return
+			}
+
+			require.NotNil(t, research)
+			assert.Equal(t, "Three members voted against the budget.", *research)
+			assert.Equal(t, 1, assistant.asks)
+		})
+	}
+}
+
+func TestResearchArticleStopsFollowUpWhenComplete(t *testing.T) {
+	gaps := []string{
+		`{"complete": false, "questions": ["Who voted against it?"]}`,
+		`{"complete": true, "questions": []}`,
+	}
+
+	assistant := &stubAssistant{
+		answer: func(persona, request string) (string, error) {
+			return "The council passed the budget.", nil
+		},
+		structured: func(persona, request string) (string, error) {
+			if strings.Contains(request, "Extract the list of facts") {
+				return `[]`, nil
+			}
+
+			response := gaps[0]
+			gaps = gaps[1:]
+
+			return response, nil
+		},
+	}
+
+	ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{ResearchDepth: 2}), assistant)
+	article, err := ResearchArticle(ctx, Article{Headline: "Council passes budget"})
+	require.NoError(t, err)
+
+	assert.Empty(t, gaps)
+	assert.Equal(t, 2, assistant.asks)
+	assert.Equal(t, 3, assistant.structuredAsks)
+	assert.Equal(t, "The council passed the budget.\n\nThe council passed the budget.", article.Research)
+}
+
+// blockingAssistant researches every story except the blocked headlines,
+// whose research is content blocked.
+func blockingAssistant(blocked ...string) *stubAssistant {
+	return &stubAssistant{
+		answer: func(persona, request string) (string, error) {
+			for _, headline := range blocked {
+				if strings.Contains(request, headline) {
+					return "", models.ErrContentBlocked
+				}
+			}
+
+			return "The council passed the budget.", nil
+		},
+		structured: func(persona, request string) (string, error) {
+			return `[]`, nil
+		},
+	}
+}
+
+func TestResearchArticleRetriesBlockedResearch(t *testing.T) {
+	tests := []struct {
+		name   string
+		retry  bool
+		asks   int
+		status ArticleStatus
+	}{
+		{"retry", true, 2, StatusResearched},
+		{"no retry", false, 1, StatusBlocked},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var personas []string
+			var requests []string
+
+			assistant := &stubAssistant{
+				answer: func(persona, request string) (string, error) {
+					personas = append(personas, persona)
+					requests = append(requests, request)
+
+					if persona == ResearchSystemPrompt {
+						return "", models.ErrContentBlocked
+					}
+
+					return "Police said one person was arrested.", nil
+				},
+				structured: func(persona, request string) (string, error) {
+					return `[]`, nil
+				},
+			}
+
+			ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{RetryBlockedResearch: test.retry}), assistant)
+			article, err := ResearchArticle(ctx, Article{Headline: "Man arrested after stabbing"})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.asks, assistant.asks)
+			assert.Equal(t, test.status, article.Status)
+
+			if test.retry {
+				assert.Equal(t, ResearchFactualSystemPrompt, personas[1])
+				assert.Contains(t, requests[1], "Compile the public factual record")
+				assert.Equal(t, "Police said one person was arrested.", article.Research)
+			}
+		})
+	}
+}
+
+func TestResearchArticleSubstitutesBlockedStories(t *testing.T) {
+	section := Section{Title: "Local"}
+	sections := []Section{section}
+
+	pool := newCandidatePool()
+	pool.add(section.Title, []Article{
+		{Headline: "Second stabbing downtown", Section: section},
+		{Headline: "Library reopens", Section: section},
+		{Headline: "Park gets new playground", Section: section},
+	})
+
+	ctx := withOptions(context.Background(), NewspaperOptions{SubstituteBlockedStories: true})
+	ctx = withAssistant(withCandidates(ctx, pool), blockingAssistant("Man arrested after stabbing", "Second stabbing downtown"))
+
+	article, err := ResearchArticle(ctx, Article{Headline: "Man arrested after stabbing", Section: section})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Library reopens", article.Headline)
+	assert.Equal(t, StatusResearched, article.Status)
+
+	replaced := pool.replacedArticles()
+	require.Len(t, replaced, 2)
+	assert.Equal(t, "Man arrested after stabbing", replaced[0].Headline)
+	assert.Equal(t, StatusBlocked, replaced[0].Status)
+	assert.Equal(t, "research content blocked; replaced by Second stabbing downtown", replaced[0].StatusReason)
+	assert.Equal(t, "Second stabbing downtown", replaced[1].Headline)
+	assert.Equal(t, "research content blocked; replaced by Library reopens", replaced[1].StatusReason)
+
+	reserved := pool.reservedArticles(sections)
+	require.Len(t, reserved, 1)
+	assert.Equal(t, "Park gets new playground", reserved[0].Headline)
+	assert.Equal(t, StatusReserved, reserved[0].Status)
+	assert.Equal(t, StagePlan, reserved[0].StatusStage)
+}
+
+func TestResearchArticleKeepsBlockedStoryWithoutReserve(t *testing.T) {
+	section := Section{Title: "Local"}
+	pool := newCandidatePool()
+
+	ctx := withOptions(context.Background(), NewspaperOptions{SubstituteBlockedStories: true})
+	ctx = withAssistant(withCandidates(ctx, pool), blockingAssistant("Man arrested after stabbing"))
+
+	article, err := ResearchArticle(ctx, Article{Headline: "Man arrested after stabbing", Section: section})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Man arrested after stabbing", article.Headline)
+	assert.Equal(t, StatusBlocked, article.Status)
+	assert.Equal(t, "research content blocked", article.StatusReason)
+	assert.Empty(t, pool.replacedArticles())
+}
@@ -0,0 +1,202 @@
+package newspaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+const (
+	ReviewSystemPrompt = `
+		You are an expert newspaper editor. Your task is to review articles
+		written by your journalists before they go to print and give them
+		specific, actionable criticism.
+		`
+
+	ReviewPrompt = `
+		## Date Range (inclusive, UTC)
+		{{.DateRange}}
+
+		## Format
+		{{.Structure}}
+
+		## Length
+		{{if .TargetLength}}Budgeted {{.TargetLength}} characters; the article has {{.Length}} characters.{{else}}The article has {{.Length}} characters.{{end}}
+
+		## Article
+		{{.Body}}
+
+		## Rubric
+		- clarity: the article is easy to follow, with the most important facts first and technical terms explained
+		- neutrality: the article is neutral and attributes opinions and assessments to sources
+		- date compliance: the article only reports events within the Date Range
+		- structure: the article follows its format and each paragraph covers one idea
+		- length: the article is close to its budgeted length without padding or repetition
+
+		## Task
+		Score the article from 1 (poor) to 5 (excellent) on each criterion of
+		the rubric. For every criterion scored below 5, list the specific
+		problems the journalist must fix, quoting the passage they concern.
+		`
+
+	ReviewReviseTask = `
+		Revise the article to address the editor's criticism.
+		`
+)
+
+const (
+	checkEditorReview = "editor-review"
+
+	// minReviewScore is the lowest rubric score that passes review.
+	minReviewScore = 4
+)
+
+// reviewCriteria are the criteria of the review rubric.
+var reviewCriteria = []string{"clarity", "neutrality", "date compliance", "structure", "length"}
+
+type reviewScore struct {
+	Criterion string   `json:"criterion"`
+	Score     int      `json:"score"`
+	Problems  []string `json:"problems"`
+}
+
+// ReviewArticle has an editor critique each synthesized article against the
+// review rubric and the journalist revise it based on the critique, for up to
+// the number of review rounds of the edition. The review never drops an
+// article; problems left after the last round are recorded in its checks.
+func ReviewArticle(ctx context.Context, article Article) (*Article, error) {
+	rounds := optionsFrom(ctx).ReviewRounds
+	if !article.Status.Active() || rounds <= 0 {
+		return &article, nil
+	}
+
+	revised := false
+
+	for round := 0; ; round++ {
+		issues, err := critiqueArticle(ctx, article)
+		if err != nil {
+			slog.Warn("editor_review_failed",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.String("error", err.Error()),
+			)
+
+			return &article, nil
+		}
+
+		if len(issues) == 0 || round == rounds {
+			slog.Info("editor_reviewed_article",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.Int("rounds", round),
+				slog.Int("issues", len(issues)),
+			)
+
+			article.AddCheck(checkEditorReview, revised, issues)
+			return &article, nil
+		}
+
+		slog.Info("editor_review_revising",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.Int("round", round+1),
+			slog.Any("issues", issues),
+		)
+
+		draft, err := reviseArticle(ctx, article, ReviewReviseTask, issues)
+		if err != nil {
+			slog.Warn("editor_review_revise_failed",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.String("error", err.Error()),
+			)
+
+			article.AddCheck(checkEditorReview, revised, issues)
+			return &article, nil
+		}
+
+		article = *draft
+		revised = true
+	}
+}
+
+// critiqueArticle asks the editor to score the article against the review
+// rubric, returning the problems of every criterion that did not pass.
+func critiqueArticle(ctx context.Context, article Article) ([]string, error) {
+	prompt, err := BuildPrompt(ReviewPrompt, PromptArgs{
+		"DateRange":    dateRangeString(ctx),
+		"Structure":    article.Format.structure(),
+		"TargetLength": article.TargetLength,
+		"Length":       len(article.Body),
+		"Body":         article.Body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("review prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"scores": map[string]any{
+				"type":        "array",
+				"description": "score of the article on each criterion of the rubric",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"criterion": map[string]any{
+							"type":        "string",
+							"enum":        reviewCriteria,
+							"description": "the rubric criterion",
+						},
+						"score": map[string]any{
+							"type":        "integer",
+							"description": "score from 1 (poor) to 5 (excellent)",
+						},
+						"problems": map[string]any{
+							"type":        "array",
+							"description": "specific problems to fix for this criterion",
+							"items": map[string]any{
+								"type": "string",
+							},
+						},
+					},
+					"required": []string{"criterion", "score", "problems"},
+				},
+			},
+		},
+		"required": []string{"scores"},
+	}
+
+	responseJson, err := structuredAsk(ctx, ReviewSystemPrompt, *prompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("review structured ask: %w", err)
+	}
+
+	var result struct {
+		Scores []reviewScore
+	}
+
+	if err := json.Unmarshal(responseJson, &result); err != nil {
+		return nil, fmt.Errorf("review unmarshal json: %w", err)
+	}
+
+	var issues []string
+
+	for _, score := range result.Scores {
+		if score.Score >= minReviewScore {
+			continue
+		}
+
+		if len(score.Problems) == 0 {
+			issues = append(issues, fmt.Sprintf("%s scored %d of 5", score.Criterion, score.Score))
+			continue
+		}
+
+		for _, problem := range score.Problems {
+			issues = append(issues, fmt.Sprintf("%s: %s", score.Criterion, problem))
+		}
+	}
+
+	return issues, nil
+}
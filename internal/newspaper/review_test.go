@@ -0,0 +1,120 @@
+package newspaper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	passingReview = `{"scores": [{"criterion": "clarity", "score": 5, "problems": []}]}`
+	failingReview = `{"scores": [{"criterion": "clarity", "score": 2, "problems": ["The lead buries the vote."]}]}`
+	reviewIssue   = "clarity: The lead buries the vote."
+)
+
+// reviewAssistant answers the editor's critiques in turn, failing once they
+// run out, and counts the critiques and revisions it was asked for.
+func reviewAssistant(critiques ...string) (*stubAssistant, *int, *int) {
+	reviews, revisions := 0, 0
+
+	assistant := &stubAssistant{
+		structured: func(persona, request string) (string, error) {
+			if persona != ReviewSystemPrompt {
+				revisions++
+				return `{"dek": "The budget passed.", "key_points": [], "paragraphs": ["The council passed the budget 12 to 3."]}`, nil
+			}
+
+			reviews++
+
+			if len(critiques) == 0 {
+				return "", assert.AnError
+			}
+
+			critique := critiques[0]
+			critiques = critiques[1:]
+
+			return critique, nil
+		},
+	}
+
+	return assistant, &reviews, &revisions
+}
+
+func TestReviewArticleRounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		rounds    int
+		critiques []string
+		reviews   int
+		revisions int
+		issues    []string
+	}{
+		{"passes first review", 1, []string{passingReview}, 1, 0, nil},
+		{"passes after revision", 2, []string{failingReview, passingReview}, 2, 1, nil},
+		{"one round", 1, []string{failingReview, failingReview, passingReview}, 2, 1, []string{reviewIssue}},
+		{"two rounds", 2, []string{failingReview, failingReview, failingReview, passingReview}, 3, 2, []string{reviewIssue}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assistant, reviews, revisions := reviewAssistant(test.critiques...)
+			ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{ReviewRounds: test.rounds}), assistant)
+
+			article := Article{Body: "The council passed the budget."}
+			article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+			reviewed, err := ReviewArticle(ctx, article)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.reviews, *reviews)
+			assert.Equal(t, test.revisions, *revisions)
+
+			// Review never rejects an article, it only records what is left.
+			assert.Equal(t, StatusSynthesized, reviewed.Status)
+
+			require.Len(t, reviewed.Checks, 1)
+			assert.Equal(t, checkEditorReview, reviewed.Checks[0].Check)
+			assert.Equal(t, test.revisions > 0, reviewed.Checks[0].Revised)
+			assert.Equal(t, test.issues, reviewed.Checks[0].Issues)
+
+			if test.revisions > 0 {
+				assert.Equal(t, "The council passed the budget 12 to 3.", reviewed.Body)
+				assert.Equal(t, "The budget passed.", reviewed.Dek)
+			}
+		})
+	}
+}
+
+func TestReviewArticleSkipped(t *testing.T) {
+	assistant, reviews, _ := reviewAssistant(failingReview)
+	ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{}), assistant)
+
+	article := Article{Body: "The council passed the budget."}
+	article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+	reviewed, err := ReviewArticle(ctx, article)
+	require.NoError(t, err)
+
+	assert.Zero(t, *reviews)
+	assert.Empty(t, reviewed.Checks)
+}
+
+func TestReviewArticleFailedCritique(t *testing.T) {
+	assistant, reviews, revisions := reviewAssistant(failingReview)
+	ctx := withAssistant(withOptions(context.Background(), NewspaperOptions{ReviewRounds: 2}), assistant)
+
+	article := Article{Body: "The council passed the budget."}
+	article.SetStatus(StageSynthesize, StatusSynthesized, "")
+
+	reviewed, err := ReviewArticle(ctx, article)
+	require.NoError(t, err)
+
+	// The revised article is kept when the editor fails to critique it again.
+	assert.Equal(t, 2, *reviews)
+	assert.Equal(t, 1, *revisions)
+	assert.Equal(t, "The council passed the budget 12 to 3.", reviewed.Body)
+	assert.Equal(t, StatusSynthesized, reviewed.Status)
+	assert.Empty(t, reviewed.Checks)
+}
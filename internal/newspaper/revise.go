@@ -0,0 +1,105 @@
+package newspaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	RevisePrompt = `
+		## Date Range (inclusive, UTC)
+		{{.DateRange}}
+
+		## Research Notes (source material)
+		{{.Research}}
+
+		## Article
+		{{.Body}}
+
+		## Dek
+		{{.Dek}}
+
+		## Key Points
+		{{range .KeyPoints}}- {{.}}
+		{{end}}
+		## Problems
+		{{range .Issues}}- {{.}}
+		{{end}}
+		## Task
+		{{.Task}}
+		Fix only the listed problems and keep the rest of the article as it is.
+		Then rewrite the dek and key points so they only say what the revised
+		article says. Respond with the revised dek, key points and paragraphs.
+		`
+)
+
+// reviseArticle asks the journalist to revise the body of an article to
+// address a list of problems. The dek and key points are rewritten from the
+// revised body so they never summarize text the revision removed.
+func reviseArticle(ctx context.Context, article Article, task string, issues []string) (*Article, error) {
+	prompt, err := BuildPrompt(RevisePrompt, PromptArgs{
+		"DateRange": dateRangeString(ctx),
+		"Research":  article.Research,
+		"Body":      article.Body,
+		"Dek":       article.Dek,
+		"KeyPoints": article.KeyPoints,
+		"Issues":    issues,
+		"Task":      task,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("revise prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"dek": map[string]any{
+				"type":        "string",
+				"description": "single sentence subheadline",
+			},
+			"key_points": map[string]any{
+				"type":        "array",
+				"description": "2 to 3 single sentence key points",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"paragraphs": map[string]any{
+				"type":        "array",
+				"description": "body paragraphs of the revised article",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+		},
+		"required": []string{"dek", "key_points", "paragraphs"},
+	}
+
+	responseJson, err := structuredAsk(ctx, article.Format.systemPrompt(), *prompt, schema)
+	if err != nil {
+		return nil, fmt.Errorf("revise structured ask: %w", err)
+	}
+
+	var draft struct {
+		Dek        string   `json:"dek"`
+		KeyPoints  []string `json:"key_points"`
+		Paragraphs []string `json:"paragraphs"`
+	}
+
+	if err := json.Unmarshal(responseJson, &draft); err != nil {
+		return nil, fmt.Errorf("revise unmarshal json: %w", err)
+	}
+
+	body := strings.TrimSpace(strings.Join(draft.Paragraphs, "\n\n"))
+	if len(body) == 0 {
+		return nil, fmt.Errorf("revise returned an empty article")
+	}
+
+	article.Dek = strings.TrimSpace(draft.Dek)
+	article.KeyPoints = draft.KeyPoints
+	article.Body = body
+
+	return &article, nil
+}
@@ -0,0 +1,50 @@
+package newspaper
+
+import (
+	"context"
+
+	"github.com/schraf/pipeline"
+)
+
+// articleStage is a pipeline stage that works on a single article.
+type articleStage func(context.Context, Article) (*Article, error)
+
+// researchStages are the stages that research a planned story, in order.
+var researchStages = []articleStage{
+	ResearchArticle,
+	QuarantineResearch,
+	CheckResearchDates,
+	CheckResearch,
+}
+
+// writingStages are the stages that write a researched story into a
+// finished article once its length is budgeted, in order.
+var writingStages = []articleStage{
+	SynthesizeArticle,
+	CheckArticleNumbers,
+	ReviewArticle,
+	CheckArticleCopy,
+	FactCheckArticle,
+	RecheckArticleNumbers,
+	CheckArticleDates,
+	NormalizeArticle,
+	RewriteHeadline,
+}
+
+// articleStages are every stage a planned story goes through to become a
+// finished article, in order.
+func articleStages() []articleStage {
+	return append(append([]articleStage(nil), researchStages...), writingStages...)
+}
+
+// transformArticles runs the articles of a channel through each of the
+// stages in turn, returning the channel of the last stage.
+func transformArticles(pipe *pipeline.Pipeline, stages []articleStage, in <-chan Article, capacity int) <-chan Article {
+	for _, stage := range stages {
+		out := make(chan Article, capacity)
+		pipeline.Transform(pipe, stage, in, out)
+		in = out
+	}
+
+	return in
+}
@@ -0,0 +1,160 @@
+package newspaper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+)
+
+const (
+	SufficiencySystemPrompt = `
+		You are an expert newspaper editor. Your task is to decide whether the
+		research gathered for a news article is sufficient to write it.
+		`
+
+	SufficiencyPrompt = `
+		## Date Range (inclusive, UTC)
+		{{.DateRange}}
+
+		## Article Headline
+		{{.Headline}}
+
+		## Event Summary
+		{{.Summary}}
+
+		## Research Notes
+		{{.Research}}
+
+		## Task
+		Decide whether the research notes contain enough information that
+		occurred within the Date Range to write a complete news article about
+		this event. The research is insufficient if the researcher reports that
+		they could not find information, if the notes are mostly about events
+		outside the Date Range, or if the notes are too thin to support more
+		than a few sentences. Give a short reason for your decision.
+		`
+)
+
+// minSupportingFacts is the number of facts that lets research pass the
+// refusal heuristic even when the researcher reports that part of the
+// story could not be found.
+const minSupportingFacts = 3
+
+// refusalModifiers and refusalSubjects are the words a researcher uses for
+// what it could not find, e.g. "any reliable information".
+const (
+	refusalModifiers = `(?:any |enough |sufficient |specific |reliable |verifiable |relevant |recent |credible |in-range |further |additional )*`
+	refusalSubjects  = `(?:information|details|reports|reporting|coverage|sources|evidence|news)\b`
+)
+
+// refusalPattern matches a researcher reporting that it could not find
+// information about the event it was asked to research: "I could not find
+// any information", "Unable to locate reliable reports" or "no information
+// is available about". Statements about the story itself, like "there were
+// no reports of injuries" or "no evidence of foul play", do not match.
+var refusalPattern = regexp.MustCompile(`(?i)(?:` +
+	`\b(?:I|we) (?:could not|couldn't|cannot|can't|can not|am unable to|are unable to|was unable to|were unable to|did not|didn't|was not able to|wasn't able to|were not able to|weren't able to) (?:find|locate|identify|verify|confirm) ` + refusalModifiers + refusalSubjects +
+	`|(?:^|[.!?:]\s+)unable to (?:find|locate|identify|verify|confirm) ` + refusalModifiers + refusalSubjects +
+	`|\b(?:no|not enough|insufficient) ` + refusalModifiers + refusalSubjects + ` (?:is |are |was |were |could be |can be )?(?:currently |publicly |readily )?(?:available|found) (?:about|on|for|regarding|concerning)\b` +
+	`)`)
+
+// CheckResearch marks articles whose research is not sufficient to write
+// an article as invalid, recording the reason, so they never reach synthesis.
+func CheckResearch(ctx context.Context, article Article) (*Article, error) {
+	if !article.Status.Active() {
+		return &article, nil
+	}
+
+	reason := researchRefusal(article)
+
+	if reason == "" && optionsFrom(ctx).ResearchJudgment {
+		judgment, err := judgeResearch(ctx, article)
+		if err != nil {
+			slog.Warn("research_judgment_failed",
+				slog.String("section", article.Section.Title),
+				slog.String("headline", article.Headline),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			reason = judgment
+		}
+	}
+
+	if reason != "" {
+		slog.Warn("research_insufficient",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.String("reason", reason),
+		)
+
+		article.SetStatus(StageSufficiency, StatusInsufficient, reason)
+	}
+
+	return &article, nil
+}
+
+// researchRefusal returns the reason research is considered a refusal, or an
+// empty string if it is not.
+func researchRefusal(article Article) string {
+	match := refusalPattern.FindString(article.Research)
+	if match == "" || len(article.Facts) >= minSupportingFacts {
+		return ""
+	}
+
+	return fmt.Sprintf("researcher reported %q with only %d supporting facts", match, len(article.Facts))
+}
+
+// judgeResearch asks the assistant whether the research is sufficient. It
+// returns the reason when it is not, or an empty string when it is.
+func judgeResearch(ctx context.Context, article Article) (string, error) {
+	prompt, err := BuildPrompt(SufficiencyPrompt, PromptArgs{
+		"DateRange": dateRangeString(ctx),
+		"Headline":  article.Headline,
+		"Summary":   article.Summary,
+		"Research":  article.Research,
+	})
+	if err != nil {
+		return "", fmt.Errorf("sufficiency prompt error: %w", err)
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"sufficient": map[string]any{
+				"type":        "boolean",
+				"description": "true if the research is sufficient to write the article",
+			},
+			"reason": map[string]any{
+				"type":        "string",
+				"description": "short reason for the decision",
+			},
+		},
+		"required": []string{"sufficient", "reason"},
+	}
+
+	responseJson, err := structuredAsk(ctx, SufficiencySystemPrompt, *prompt, schema)
+	if err != nil {
+		return "", fmt.Errorf("sufficiency structured ask: %w", err)
+	}
+
+	var judgment struct {
+		Sufficient bool
+		Reason     string
+	}
+
+	if err := json.Unmarshal(responseJson, &judgment); err != nil {
+		return "", fmt.Errorf("sufficiency unmarshal json: %w", err)
+	}
+
+	if judgment.Sufficient {
+		return "", nil
+	}
+
+	if judgment.Reason == "" {
+		return "assistant judged the research insufficient", nil
+	}
+
+	return "assistant judged the research insufficient: " + judgment.Reason, nil
+}
@@ -0,0 +1,32 @@
+package newspaper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResearchRefusal(t *testing.T) {
+	tests := []struct {
+		research string
+		facts    int
+		refused  bool
+	}{
+		{"I could not find any information about the merger.", 0, true},
+		{"We were unable to locate reliable reports on the vote.", 1, true},
+		{"Unable to find specific details within the date range.", 0, true},
+		{"Search summary: unable to verify any reports of the closure.", 0, true},
+		{"No information is available about this event.", 2, true},
+		{"There is not enough reliable coverage available on the hearing.", 0, true},
+		{"I could not find any information about the merger.", 3, false},
+		{"There were no reports of injuries.", 2, false},
+		{"Police said there was no evidence of foul play.", 0, false},
+		{"Officials could not confirm reports of a second explosion.", 1, false},
+		{"The company gave no details on the layoffs.", 0, false},
+	}
+
+	for _, test := range tests {
+		article := Article{Research: test.research, Facts: make([]Fact, test.facts)}
+		assert.Equal(t, test.refused, researchRefusal(article) != "", test.research)
+	}
+}
@@ -2,23 +2,24 @@ package newspaper
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
+
+	"github.com/schraf/assistant/pkg/models"
 )
 
 const (
 	SynthesizeSystemPrompt = `
 		You are an expert newspaper journalist. Your task is to take researched
 		information about a recent event and synthesize a complete newspaper 
-		article. The article should contain at least 5 to 8 paragraphs and each
-		paragraph should have at least 4 sentences. Do not include any headings
-		or Markdown, HTML, LaTeX, or escape characters. The article should be 
-		written in clear, neutral, newspaper-style English.
-
-		The user will provide a Date Range. Treat it as a hard constraint:
-		only include events, developments, and data points that occurred within
-		the Date Range (inclusive). Do not include background/history outside
-		the Date Range. If a claim is undated or the date is ambiguous, omit it.
-		`
+		article of the length the user asks for, where each paragraph has 3 to
+		5 sentences. Do not include any headings or Markdown, HTML, LaTeX, or
+		escape characters. The article should be written in clear, neutral,
+		newspaper-style English.
+		` + synthesizeDateRangeRule
 
 	SynthesizePrompt = `
 		## Date Range (inclusive, UTC)
@@ -27,16 +28,36 @@ const (
 		## Research Notes (source material)
 		{{.Research}}
 
+		## Format
+		{{.Structure}}
+
+		## Length
+		{{if .TargetLength}}About {{.TargetLength}} characters, in about {{.Paragraphs}} paragraphs.{{else}}5 to 8 paragraphs, each with at least 4 sentences.{{end}}
+
 		## Task
 		Write the article using ONLY information within the Date Range (inclusive).
-		Omit anything outside the range or with unclear timing.
+		Omit anything outside the range or with unclear timing. Provide:
+			- a dek: a single sentence subheadline that adds to the headline
+			- a dateline: the city where the main event took place in upper case, followed by the date it took place, e.g. "GENEVA, March 10"
+			- a byline label naming the desk that reported the story, e.g. "By the World News Desk" (never a person's name)
+			- 2 to 3 key points, each a single sentence summarizing the article
+			- the body paragraphs of the article, without the dateline
+		{{if .Corrections}}
+		## Corrections
+		A previous draft of this article had the following problems. Do not repeat them.
+		{{range .Corrections}}- {{.}}
+		{{end}}{{end}}
 		`
 )
 
 func SynthesizeArticle(ctx context.Context, article Article) (*Article, error) {
 	prompt, err := BuildPrompt(SynthesizePrompt, PromptArgs{
-		"DateRange": dateRangeString(ctx),
-		"Research":  article.Research,
+		"DateRange":    dateRangeString(ctx),
+		"Research":     article.Research,
+		"Corrections":  article.Corrections,
+		"Structure":    article.Format.structure(),
+		"TargetLength": article.TargetLength,
+		"Paragraphs":   targetParagraphs(article.TargetLength),
 	})
 	if err != nil {
 		slog.Warn("synthesizing_article_prompt_failed",
@@ -45,11 +66,58 @@ func SynthesizeArticle(ctx context.Context, article Article) (*Article, error) {
 			slog.String("error", err.Error()),
 		)
 
-		article.Valid = false
+		article.SetStatus(StageSynthesize, StatusFailed, "synthesis prompt failed: "+err.Error())
 		return &article, nil
 	}
 
-	body, err := ask(ctx, SynthesizeSystemPrompt, *prompt)
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"dek": map[string]any{
+				"type":        "string",
+				"description": "single sentence subheadline",
+			},
+			"dateline": map[string]any{
+				"type":        "string",
+				"description": "city in upper case and date of the main event",
+			},
+			"byline": map[string]any{
+				"type":        "string",
+				"description": "byline label naming the reporting desk",
+			},
+			"key_points": map[string]any{
+				"type":        "array",
+				"description": "2 to 3 single sentence key points",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+			"paragraphs": map[string]any{
+				"type":        "array",
+				"description": "body paragraphs of the article",
+				"items": map[string]any{
+					"type": "string",
+				},
+			},
+		},
+		"required": []string{"dek", "dateline", "byline", "key_points", "paragraphs"},
+	}
+
+	var draft struct {
+		Dek        string   `json:"dek"`
+		Dateline   string   `json:"dateline"`
+		Byline     string   `json:"byline"`
+		KeyPoints  []string `json:"key_points"`
+		Paragraphs []string `json:"paragraphs"`
+	}
+
+	responseJson, err := structuredAsk(ctx, article.Format.systemPrompt(), *prompt, schema)
+	if err == nil {
+		if err = json.Unmarshal(responseJson, &draft); err == nil && len(draft.Paragraphs) == 0 {
+			err = fmt.Errorf("no paragraphs")
+		}
+	}
+
 	if err != nil {
 		slog.Warn("synthesizing_article_failed",
 			slog.String("section", article.Section.Title),
@@ -57,14 +125,23 @@ func SynthesizeArticle(ctx context.Context, article Article) (*Article, error) {
 			slog.String("error", err.Error()),
 		)
 
-		article.Valid = false
+		if errors.Is(err, models.ErrContentBlocked) {
+			article.SetStatus(StageSynthesize, StatusBlocked, "synthesis content blocked")
+		} else {
+			article.SetStatus(StageSynthesize, StatusFailed, "synthesis failed: "+err.Error())
+		}
 	} else {
-		article.Valid = true
-		article.Body = *body
+		article.SetStatus(StageSynthesize, StatusSynthesized, "")
+		article.Dek = strings.TrimSpace(draft.Dek)
+		article.Dateline = strings.TrimSpace(draft.Dateline)
+		article.Byline = strings.TrimSpace(draft.Byline)
+		article.KeyPoints = draft.KeyPoints
+		article.Body = strings.Join(draft.Paragraphs, "\n\n")
 
 		slog.Info("synthesized_article",
 			slog.String("section", article.Section.Title),
 			slog.String("headline", article.Headline),
+			slog.String("format", string(article.Format)),
 			slog.Int("body", len(article.Body)),
 		)
 	}
@@ -0,0 +1,159 @@
+package newspaper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/schraf/assistant/pkg/models"
+)
+
+const (
+	CondensePrompt = `
+		## Article
+		{{.Body}}
+
+		## Task
+		The article is too long for the newspaper. Condense it to at most
+		{{.TargetLength}} characters, in about {{.Paragraphs}} paragraphs,
+		keeping the most important facts and dropping the least important
+		details. Do not add anything that is not in the article.
+		Respond with the complete condensed article and nothing else.
+		`
+)
+
+const (
+	checkTrim = "trim"
+
+	// minTrimParagraphs is the fewest body paragraphs a trimmed article keeps.
+	minTrimParagraphs = 2
+
+	// minTrimFraction is the smallest fraction of its body, in percent, a
+	// trimmed article keeps.
+	minTrimFraction = 50
+
+	// wholeWorth is the worth of an article kept whole, in percent of its
+	// score. A trimmed article is worth half its score plus half the
+	// fraction of the body it keeps, so trimming costs less than cutting.
+	wholeWorth = 100
+)
+
+// editOption is one way the editor can keep an article in the edition.
+type editOption struct {
+	article Article
+	section models.DocumentSection
+	// length is the length the option adds to the document.
+	length int
+	// worth is the value of the option, in percent of the article's score.
+	worth int
+}
+
+// trimOptions lists the ways an article can be kept: whole, followed by
+// trims that drop its last body paragraphs. Stories are written with the most
+// important information first, so the last paragraphs are the lowest value.
+// Only formats whose last paragraphs matter least are trimmed.
+func trimOptions(ctx context.Context, article Article, section models.DocumentSection) []editOption {
+	options := []editOption{{
+		article: article,
+		section: section,
+		length:  sectionLength(section),
+		worth:   wholeWorth,
+	}}
+
+	if !article.Format.trimmable() {
+		return options
+	}
+
+	paragraphs := strings.Split(article.Body, "\n\n")
+	bodyLength := len(article.Body)
+
+	for count := len(paragraphs) - 1; count >= minTrimParagraphs; count-- {
+		trimmed := article
+		trimmed.Body = strings.Join(paragraphs[:count], "\n\n")
+
+		fraction := len(trimmed.Body) * 100 / max(bodyLength, 1)
+		if fraction < minTrimFraction {
+			break
+		}
+
+		trimmed.AddCheck(checkTrim, true, nil)
+		section := renderArticle(ctx, trimmed)
+
+		options = append(options, editOption{
+			article: trimmed,
+			section: section,
+			length:  sectionLength(section),
+			worth:   (wholeWorth + fraction) / 2,
+		})
+	}
+
+	return options
+}
+
+// condenseArticle asks the journalist to condense an article that was chosen
+// to be trimmed to the length of the trim. The paragraph trim is kept if the
+// rewrite fails or is no shorter.
+func condenseArticle(ctx context.Context, article Article, trim editOption) editOption {
+	condensed, err := condensedOption(ctx, article, trim)
+	if err != nil {
+		slog.Warn("condense_article_failed",
+			slog.String("section", article.Section.Title),
+			slog.String("headline", article.Headline),
+			slog.String("error", err.Error()),
+		)
+
+		return trim
+	}
+
+	return *condensed
+}
+
+// condensedOption rewrites the article to the length of the trim and returns
+// it as an edit option.
+func condensedOption(ctx context.Context, article Article, trim editOption) (*editOption, error) {
+	targetLength := len(trim.article.Body)
+
+	prompt, err := BuildPrompt(CondensePrompt, PromptArgs{
+		"Body":         article.Body,
+		"TargetLength": targetLength,
+		"Paragraphs":   targetParagraphs(targetLength),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("condense prompt error: %w", err)
+	}
+
+	body, err := ask(ctx, article.Format.systemPrompt(), *prompt)
+	if err != nil {
+		return nil, fmt.Errorf("condense ask: %w", err)
+	}
+
+	paragraphs, _ := normalizeBody(*body)
+	if len(paragraphs) == 0 || truncated(paragraphs[len(paragraphs)-1]) {
+		return nil, fmt.Errorf("condensed article is empty or truncated")
+	}
+
+	article.Body = strings.Join(paragraphs, "\n\n")
+	article.AddCheck(checkTrim, true, nil)
+
+	option := editOption{
+		article: article,
+		section: renderArticle(ctx, article),
+		worth:   trim.worth,
+	}
+
+	option.length = sectionLength(option.section)
+	if option.length > trim.length {
+		return nil, fmt.Errorf("condensed article is %d characters, longer than the trim", option.length)
+	}
+
+	return &option, nil
+}
+
+// renderArticle renders an article on its own and returns its section.
+func renderArticle(ctx context.Context, article Article) models.DocumentSection {
+	doc := models.Document{}
+	addArticle(ctx, &doc, article)
+
+	return doc.Sections[0]
+}
@@ -3,6 +3,7 @@ package generator
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -11,15 +12,55 @@ import (
 	"github.com/schraf/newspaper-assistant/internal/newspaper"
 )
 
+// maxResearchDepth is the deepest supported research depth (long).
+const maxResearchDepth = 2
+
+// maxReviewRounds is the most editor review rounds an article may get.
+const maxReviewRounds = 2
+
 func init() {
 	generators.MustRegister("newspaper", factory)
 }
 
-func factory(generators.Config) (models.ContentGenerator, error) {
-	return &generator{}, nil
+// factory creates the newspaper generator. The research cache is configured
+// through the generator config rather than the request, since it refers to
+// the local filesystem:
+//   - research_cache_dir: directory to cache research in (disabled if empty)
+//   - research_cache_ttl: how long cached research stays fresh, e.g. "24h"
+func factory(config generators.Config) (models.ContentGenerator, error) {
+	cacheDir := strings.TrimSpace(toString(config["research_cache_dir"]))
+
+	var cacheTTL time.Duration
+
+	switch ttl := config["research_cache_ttl"].(type) {
+	case nil:
+	case time.Duration:
+		cacheTTL = ttl
+	case string:
+		duration, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'research_cache_ttl' %q: %w", ttl, err)
+		}
+
+		cacheTTL = duration
+	default:
+		return nil, fmt.Errorf("invalid 'research_cache_ttl' (expected duration string)")
+	}
+
+	if cacheTTL < 0 {
+		return nil, fmt.Errorf("invalid 'research_cache_ttl' %s (must not be negative)", cacheTTL)
+	}
+
+	return &generator{
+		cacheDir: cacheDir,
+		cacheTTL: cacheTTL,
+	}, nil
 }
 
-type generator struct{}
+type generator struct {
+	cacheDir string
+	cacheTTL time.Duration
+}
 
 func (g *generator) Generate(ctx context.Context, request models.ContentRequest, assistant models.Assistant) (*models.Document, error) {
 	daysBack, ok := toInt(request.Body["days_back"])
@@ -40,34 +81,313 @@ func (g *generator) Generate(ctx context.Context, request models.ContentRequest,
 		return nil, fmt.Errorf("invalid 'max_length' %d (must be positive)", maxLength)
 	}
 
-	section := newspaper.Section{
-		Title:       strings.TrimSpace(toString(request.Body["section_title"])),
-		Description: strings.TrimSpace(toString(request.Body["section_description"])),
+	sections, err := toSections(request.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	if section.Title == "" {
-		return nil, fmt.Errorf("no 'section_title' provided")
+	includeSources, ok := toBool(request.Body["include_sources"])
+	if !ok && request.Body["include_sources"] != nil {
+		return nil, fmt.Errorf("invalid 'include_sources' (expected boolean)")
 	}
 
-	if section.Description == "" {
-		return nil, fmt.Errorf("no 'section_description' provided")
+	researchDepth, ok := toInt(request.Body["research_depth"])
+	if !ok && request.Body["research_depth"] != nil {
+		return nil, fmt.Errorf("invalid 'research_depth' (expected integer)")
+	}
+
+	if researchDepth < 0 || researchDepth > maxResearchDepth {
+		return nil, fmt.Errorf("invalid 'research_depth' %d (must be between 0 and %d)", researchDepth, maxResearchDepth)
+	}
+
+	editionPolicy, err := toSourcePolicy(request.Body, "")
+	if err != nil {
+		return nil, err
+	}
+
+	researchJudgment, ok := toBool(request.Body["research_judgment"])
+	if !ok && request.Body["research_judgment"] != nil {
+		return nil, fmt.Errorf("invalid 'research_judgment' (expected boolean)")
+	}
+
+	articlesPerSection, ok := toInt(request.Body["articles_per_section"])
+	if !ok && request.Body["articles_per_section"] != nil {
+		return nil, fmt.Errorf("invalid 'articles_per_section' (expected integer)")
+	}
+
+	if articlesPerSection < 0 {
+		return nil, fmt.Errorf("invalid 'articles_per_section' %d (must not be negative)", articlesPerSection)
+	}
+
+	retryBlocked, ok := toBool(request.Body["retry_blocked_research"])
+	if !ok && request.Body["retry_blocked_research"] != nil {
+		return nil, fmt.Errorf("invalid 'retry_blocked_research' (expected boolean)")
+	}
+
+	substituteBlocked, ok := toBool(request.Body["substitute_blocked_stories"])
+	if !ok && request.Body["substitute_blocked_stories"] != nil {
+		return nil, fmt.Errorf("invalid 'substitute_blocked_stories' (expected boolean)")
+	}
+
+	factCheck := newspaper.FactCheckMode(strings.TrimSpace(toString(request.Body["fact_check"])))
+
+	switch factCheck {
+	case "", newspaper.FactCheckOff, newspaper.FactCheckRevise, newspaper.FactCheckDrop:
+	default:
+		return nil, fmt.Errorf("invalid 'fact_check' %q (expected off, revise or drop)", factCheck)
+	}
+
+	dateStrictness := newspaper.DateStrictness(strings.TrimSpace(toString(request.Body["date_strictness"])))
+
+	switch dateStrictness {
+	case "", newspaper.DateStrictnessOff, newspaper.DateStrictnessWarn, newspaper.DateStrictnessRevise, newspaper.DateStrictnessDrop:
+	default:
+		return nil, fmt.Errorf("invalid 'date_strictness' %q (expected off, warn, revise or drop)", dateStrictness)
+	}
+
+	numberCheck, ok := toBool(request.Body["number_check"])
+	if !ok && request.Body["number_check"] != nil {
+		return nil, fmt.Errorf("invalid 'number_check' (expected boolean)")
+	}
+
+	copyThreshold, ok := toFloat(request.Body["copy_threshold"])
+	if !ok && request.Body["copy_threshold"] != nil {
+		return nil, fmt.Errorf("invalid 'copy_threshold' (expected number)")
+	}
+
+	if copyThreshold < 0 || copyThreshold > 1 {
+		return nil, fmt.Errorf("invalid 'copy_threshold' %g (must be between 0 and 1)", copyThreshold)
+	}
+
+	reviewRounds, ok := toInt(request.Body["review_rounds"])
+	if !ok && request.Body["review_rounds"] != nil {
+		return nil, fmt.Errorf("invalid 'review_rounds' (expected integer)")
+	}
+
+	if reviewRounds < 0 || reviewRounds > maxReviewRounds {
+		return nil, fmt.Errorf("invalid 'review_rounds' %d (must be between 0 and %d)", reviewRounds, maxReviewRounds)
+	}
+
+	condenseTrimmed, ok := toBool(request.Body["condense_trimmed"])
+	if !ok && request.Body["condense_trimmed"] != nil {
+		return nil, fmt.Errorf("invalid 'condense_trimmed' (expected boolean)")
+	}
+
+	minFill, ok := toFloat(request.Body["min_fill"])
+	if !ok && request.Body["min_fill"] != nil {
+		return nil, fmt.Errorf("invalid 'min_fill' (expected number)")
+	}
+
+	if minFill < 0 || minFill > 1 {
+		return nil, fmt.Errorf("invalid 'min_fill' %g (must be between 0 and 1)", minFill)
+	}
+
+	bypassCache, ok := toBool(request.Body["research_cache_bypass"])
+	if !ok && request.Body["research_cache_bypass"] != nil {
+		return nil, fmt.Errorf("invalid 'research_cache_bypass' (expected boolean)")
 	}
 
 	options := newspaper.NewspaperOptions{
-		DaysBack:  daysBack,
-		MaxLength: maxLength,
+		DaysBack:         daysBack,
+		MaxLength:        maxLength,
+		IncludeSources:   includeSources,
+		ResearchDepth:    researchDepth,
+		SourcePolicy:     *editionPolicy,
+		ResearchJudgment: researchJudgment,
+
+		ArticlesPerSection:       articlesPerSection,
+		RetryBlockedResearch:     retryBlocked,
+		SubstituteBlockedStories: substituteBlocked,
+		FactCheck:                factCheck,
+		DateStrictness:           dateStrictness,
+		NumberCheck:              numberCheck,
+		CopyThreshold:            copyThreshold,
+		ReviewRounds:             reviewRounds,
+		CondenseTrimmed:          condenseTrimmed,
+		MinFill:                  minFill,
+
+		ResearchCacheDir: g.cacheDir,
+		ResearchCacheTTL: g.cacheTTL,
+	}
+
+	if bypassCache {
+		options.ResearchCacheDir = ""
 	}
 
-	doc, err := newspaper.CreateNewspaper(ctx, assistant, section, options)
+	edition, err := newspaper.CreateNewspaper(ctx, assistant, sections, options)
 	if err != nil {
 		return nil, err
 	}
 
-	doc.Title = section.Title + ": " + dateRangeText(daysBack)
+	for _, report := range edition.Report() {
+		slog.Info("article_report",
+			slog.String("section", report.Section),
+			slog.String("headline", report.Headline),
+			slog.Bool("published", report.Published),
+			slog.String("status", string(report.Status)),
+			slog.String("stage", string(report.Stage)),
+			slog.String("reason", report.Reason),
+			slog.Any("checks", report.Checks),
+		)
+	}
+
+	doc := &edition.Document
+	doc.Title = editionTitle(request.Body, sections) + ": " + dateRangeText(daysBack)
 
 	return doc, nil
 }
 
+// toSections reads the sections of the edition: either a 'sections' list of
+// objects with the section keys, or a single section from the 'section_'
+// prefixed keys of the request body.
+func toSections(body map[string]any) ([]newspaper.Section, error) {
+	if body["sections"] == nil {
+		section, err := toSection(body, "section_")
+		if err != nil {
+			return nil, err
+		}
+
+		return []newspaper.Section{*section}, nil
+	}
+
+	var items []map[string]any
+
+	switch typedValue := body["sections"].(type) {
+	case []map[string]any:
+		items = typedValue
+	case []any:
+		for _, item := range typedValue {
+			itemMap, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid 'sections' (expected list of objects)")
+			}
+
+			items = append(items, itemMap)
+		}
+	default:
+		return nil, fmt.Errorf("invalid 'sections' (expected list of objects)")
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no 'sections' provided")
+	}
+
+	var sections []newspaper.Section
+	titles := map[string]bool{}
+	minShares := 0.0
+
+	for index, item := range items {
+		section, err := toSection(item, "")
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %w", index+1, err)
+		}
+
+		if titles[section.Title] {
+			return nil, fmt.Errorf("duplicate section title '%s'", section.Title)
+		}
+
+		titles[section.Title] = true
+		minShares += section.MinShare
+		sections = append(sections, *section)
+	}
+
+	if minShares > 1 {
+		return nil, fmt.Errorf("invalid 'min_share' of sections (total %g is more than 1)", minShares)
+	}
+
+	return sections, nil
+}
+
+// toSection reads a section from the title, description, source policy and
+// editing limit keys, each prefixed with prefix.
+func toSection(body map[string]any, prefix string) (*newspaper.Section, error) {
+	section := newspaper.Section{
+		Title:       strings.TrimSpace(toString(body[prefix+"title"])),
+		Description: strings.TrimSpace(toString(body[prefix+"description"])),
+	}
+
+	if section.Title == "" {
+		return nil, fmt.Errorf("no '%stitle' provided", prefix)
+	}
+
+	if section.Description == "" {
+		return nil, fmt.Errorf("no '%sdescription' provided", prefix)
+	}
+
+	policy, err := toSourcePolicy(body, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	section.SourcePolicy = *policy
+
+	counts := []struct {
+		key   string
+		value *int
+	}{
+		{prefix + "min_articles", &section.MinArticles},
+		{prefix + "max_articles", &section.MaxArticles},
+	}
+
+	for _, count := range counts {
+		value, ok := toInt(body[count.key])
+		if !ok && body[count.key] != nil {
+			return nil, fmt.Errorf("invalid '%s' (expected integer)", count.key)
+		}
+
+		if value < 0 {
+			return nil, fmt.Errorf("invalid '%s' %d (must not be negative)", count.key, value)
+		}
+
+		*count.value = value
+	}
+
+	if section.MaxArticles > 0 && section.MinArticles > section.MaxArticles {
+		return nil, fmt.Errorf("invalid '%smin_articles' %d (more than '%smax_articles' %d)", prefix, section.MinArticles, prefix, section.MaxArticles)
+	}
+
+	shares := []struct {
+		key   string
+		value *float64
+	}{
+		{prefix + "min_share", &section.MinShare},
+		{prefix + "max_share", &section.MaxShare},
+	}
+
+	for _, share := range shares {
+		value, ok := toFloat(body[share.key])
+		if !ok && body[share.key] != nil {
+			return nil, fmt.Errorf("invalid '%s' (expected number)", share.key)
+		}
+
+		if value < 0 || value > 1 {
+			return nil, fmt.Errorf("invalid '%s' %g (must be between 0 and 1)", share.key, value)
+		}
+
+		*share.value = value
+	}
+
+	if section.MaxShare > 0 && section.MinShare > section.MaxShare {
+		return nil, fmt.Errorf("invalid '%smin_share' %g (more than '%smax_share' %g)", prefix, section.MinShare, prefix, section.MaxShare)
+	}
+
+	return &section, nil
+}
+
+// editionTitle is the 'title' of the request, defaulting to the title of the
+// section for single section editions.
+func editionTitle(body map[string]any, sections []newspaper.Section) string {
+	if title := strings.TrimSpace(toString(body["title"])); title != "" {
+		return title
+	}
+
+	if len(sections) == 1 {
+		return sections[0].Title
+	}
+
+	return "The Daily News"
+}
+
 func toString(value any) string {
 	valueString, _ := value.(string)
 	return valueString
@@ -86,6 +406,86 @@ func toInt(value any) (int, bool) {
 	}
 }
 
+func toFloat(value any) (float64, bool) {
+	switch typedValue := value.(type) {
+	case int:
+		return float64(typedValue), true
+	case int64:
+		return float64(typedValue), true
+	case float64:
+		return typedValue, true
+	default:
+		return 0, false
+	}
+}
+
+// toStrings accepts either a list of strings or a single comma separated
+// string, returning the trimmed non-empty values.
+func toStrings(value any) ([]string, bool) {
+	var values []string
+
+	switch typedValue := value.(type) {
+	case nil:
+		return nil, true
+	case string:
+		values = strings.Split(typedValue, ",")
+	case []string:
+		values = typedValue
+	case []any:
+		for _, item := range typedValue {
+			itemString, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+
+			values = append(values, itemString)
+		}
+	default:
+		return nil, false
+	}
+
+	var result []string
+
+	for _, item := range values {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result, true
+}
+
+// toSourcePolicy reads the allowed_domains, blocked_domains and
+// preferred_outlets keys, each prefixed with prefix, from the request body.
+func toSourcePolicy(body map[string]any, prefix string) (*newspaper.SourcePolicy, error) {
+	var policy newspaper.SourcePolicy
+
+	fields := []struct {
+		key    string
+		values *[]string
+	}{
+		{prefix + "allowed_domains", &policy.AllowedDomains},
+		{prefix + "blocked_domains", &policy.BlockedDomains},
+		{prefix + "preferred_outlets", &policy.PreferredOutlets},
+	}
+
+	for _, field := range fields {
+		values, ok := toStrings(body[field.key])
+		if !ok {
+			return nil, fmt.Errorf("invalid '%s' (expected list of strings)", field.key)
+		}
+
+		*field.values = values
+	}
+
+	return &policy, nil
+}
+
+func toBool(value any) (bool, bool) {
+	valueBool, ok := value.(bool)
+	return valueBool, ok
+}
+
 func dateRangeText(daysBack int) string {
 	end := time.Now().UTC()
 	start := end.AddDate(0, 0, -daysBack)
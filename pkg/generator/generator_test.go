@@ -1,8 +1,11 @@
 package generator
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/schraf/assistant/pkg/eval"
@@ -32,3 +35,186 @@ func TestGenerator(t *testing.T) {
 	err = eval.Evaluate(ctx, generator, request, nil)
 	assert.NoError(t, err)
 }
+
+func TestGeneratorInvalidIncludeSources(t *testing.T) {
+	request := models.ContentRequest{
+		Body: map[string]any{
+			"days_back":           7,
+			"max_length":          1000,
+			"section_title":       "World News",
+			"section_description": "Significant international events and developments",
+			"include_sources":     "yes",
+		},
+	}
+
+	generator, err := generators.Create("newspaper", nil)
+	require.NoError(t, err)
+
+	_, err = generator.Generate(context.Background(), request, nil)
+	assert.Error(t, err)
+}
+
+func TestGeneratorResearchCache(t *testing.T) {
+	os.Setenv("ASSISTANT_PROVIDER", "mock")
+
+	cacheDir := t.TempDir()
+
+	body := map[string]any{
+		"days_back":           7,
+		"max_length":          1000,
+		"section_title":       "World News",
+		"section_description": "Significant international events and developments",
+	}
+
+	ctx := context.Background()
+
+	evaluate := func(config generators.Config, body map[string]any) string {
+		logs := captureLogs(t)
+
+		generator, err := generators.Create("newspaper", config)
+		require.NoError(t, err)
+
+		err = eval.Evaluate(ctx, generator, models.ContentRequest{Body: body}, nil)
+		require.NoError(t, err)
+
+		return logs.String()
+	}
+
+	config := generators.Config{
+		"research_cache_dir": cacheDir,
+		"research_cache_ttl": "1h",
+	}
+
+	logs := evaluate(config, body)
+	assert.NotContains(t, logs, "research_cache_hit")
+	assert.Contains(t, logs, "researched_article")
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.NotEmpty(t, entries)
+
+	logs = evaluate(config, body)
+	assert.Contains(t, logs, "research_cache_hit")
+	assert.NotContains(t, logs, "researched_article")
+
+	bypass := map[string]any{"research_cache_bypass": true}
+	for key, value := range body {
+		bypass[key] = value
+	}
+
+	logs = evaluate(config, bypass)
+	assert.NotContains(t, logs, "research_cache_hit")
+	assert.Contains(t, logs, "researched_article")
+
+	logs = evaluate(generators.Config{
+		"research_cache_dir": cacheDir,
+		"research_cache_ttl": "1ns",
+	}, body)
+	assert.NotContains(t, logs, "research_cache_hit")
+	assert.Contains(t, logs, "researched_article")
+}
+
+// captureLogs sends the default logger to a buffer for the rest of the test.
+func captureLogs(t *testing.T) *syncBuffer {
+	logs := &syncBuffer{}
+	previous := slog.Default()
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return logs
+}
+
+// syncBuffer is a buffer that is safe to write from several goroutines.
+type syncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buffer.String()
+}
+
+func TestGeneratorSections(t *testing.T) {
+	os.Setenv("ASSISTANT_PROVIDER", "mock")
+
+	request := models.ContentRequest{
+		Body: map[string]any{
+			"days_back":  7,
+			"max_length": 1000,
+			"sections": []any{
+				map[string]any{
+					"title":        "World News",
+					"description":  "Significant international events and developments",
+					"min_articles": 1,
+					"max_share":    0.6,
+				},
+				map[string]any{
+					"title":        "Technology",
+					"description":  "Developments in technology and the technology industry",
+					"max_articles": 2,
+				},
+			},
+		},
+	}
+
+	generator, err := generators.Create("newspaper", nil)
+	require.NoError(t, err)
+
+	err = eval.Evaluate(context.Background(), generator, request, nil)
+	assert.NoError(t, err)
+}
+
+func TestGeneratorInvalidSections(t *testing.T) {
+	request := models.ContentRequest{
+		Body: map[string]any{
+			"days_back":  7,
+			"max_length": 1000,
+			"sections": []any{
+				map[string]any{
+					"title":        "World News",
+					"description":  "Significant international events and developments",
+					"min_articles": 3,
+					"max_articles": 2,
+				},
+			},
+		},
+	}
+
+	generator, err := generators.Create("newspaper", nil)
+	require.NoError(t, err)
+
+	_, err = generator.Generate(context.Background(), request, nil)
+	assert.Error(t, err)
+}
+
+func TestGeneratorMinFill(t *testing.T) {
+	os.Setenv("ASSISTANT_PROVIDER", "mock")
+
+	request := models.ContentRequest{
+		Body: map[string]any{
+			"days_back":            7,
+			"max_length":           1000,
+			"section_title":        "World News",
+			"section_description":  "Significant international events and developments",
+			"articles_per_section": 1,
+			"min_fill":             0.8,
+		},
+	}
+
+	generator, err := generators.Create("newspaper", nil)
+	require.NoError(t, err)
+
+	err = eval.Evaluate(context.Background(), generator, request, nil)
+	assert.NoError(t, err)
+}